@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/recipes"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// runGenerateRecipes implements the generate-recipes command, which writes a
+// migration recipe for the deprecated properties of configuration metadata
+func runGenerateRecipes(args []string) {
+	flags := flag.NewFlagSet("generate-recipes", flag.ExitOnError)
+	var (
+		name        = flags.String("name", "", "Fully qualified name of the generated recipe")
+		displayName = flags.String("display-name", "", "Display name of the generated recipe (optional)")
+		description = flags.String("description", "", "Description of the generated recipe (optional)")
+		outputPath  = flags.String("output", "", "Recipe YAML file to write (optional, defaults to standard output)")
+		deprecated  = flags.Bool("comment-out-deprecated", false, "Also comment out warning-level deprecations without a replacement")
+		debug       = flags.Bool("debug", false, "Enable debug logging")
+		help        = flags.Bool("help", false, "Show help")
+	)
+	flags.Parse(args)
+
+	if *help {
+		showGenerateRecipesHelp()
+		return
+	}
+
+	if *name == "" {
+		fmt.Fprintf(os.Stderr, "Error: name is required\n")
+		os.Exit(1)
+	}
+
+	if flags.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Error: at least one jar, directory or metadata file is required\n")
+		os.Exit(1)
+	}
+
+	metadata, err := utils.LoadConfigurationMetadata(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	generator := recipes.NewMigrationRecipeGenerator(*name)
+	if *displayName != "" {
+		generator.DisplayName = *displayName
+	}
+	if *description != "" {
+		generator.Description = *description
+	}
+	generator.CommentOutDeprecated = *deprecated
+	recipe := generator.Generate(metadata)
+
+	// Standard output only carries the recipe
+	if *outputPath == "" {
+		fmt.Print(recipe)
+		return
+	}
+
+	logger := core.NewConsoleLogger(*debug)
+	deprecations := generator.Deprecations(metadata)
+	for _, property := range deprecations {
+		logger.Debug("Deprecated: %s (%s)", property.Name, property.Source)
+	}
+	if err := ioutil.WriteFile(*outputPath, []byte(recipe), 0644); err != nil {
+		logger.Error("failed to write %s: %v", *outputPath, err)
+		os.Exit(1)
+	}
+	logger.Info("Wrote %s migrating %d deprecated properties out of %d",
+		*outputPath, len(deprecations), len(metadata.Properties))
+}
+
+func showGenerateRecipesHelp() {
+	fmt.Println("rewrite-spring-go generate-recipes - Generate a migration recipe from configuration metadata")
+	fmt.Println()
+	fmt.Println("USAGE:")
+	fmt.Println("  rewrite-spring-go generate-recipes [OPTIONS] PATH...")
+	fmt.Println()
+	fmt.Println("  Reads META-INF/spring-configuration-metadata.json from each PATH, which is a jar,")
+	fmt.Println("  a directory searched for jars and metadata files, or a metadata JSON file, and")
+	fmt.Println("  writes a declarative recipe that renames deprecated properties with a replacement")
+	fmt.Println("  and comments out removed properties, those deprecated at level \"error\".")
+	fmt.Println("  Properties deprecated at level \"warning\" without a replacement are still bound")
+	fmt.Println("  and are left alone unless -comment-out-deprecated is given.")
+	fmt.Println()
+	fmt.Println("OPTIONS:")
+	fmt.Println("  -name string")
+	fmt.Println("        Fully qualified name of the generated recipe (required)")
+	fmt.Println("  -display-name string")
+	fmt.Println("        Display name of the generated recipe (optional)")
+	fmt.Println("  -description string")
+	fmt.Println("        Description of the generated recipe (optional)")
+	fmt.Println("  -output string")
+	fmt.Println("        Recipe YAML file to write (optional, defaults to standard output)")
+	fmt.Println("  -comment-out-deprecated")
+	fmt.Println("        Also comment out warning-level deprecations without a replacement")
+	fmt.Println("  -debug")
+	fmt.Println("        Enable debug logging")
+	fmt.Println("  -help")
+	fmt.Println("        Show this help message")
+	fmt.Println()
+	fmt.Println("EXAMPLES:")
+	fmt.Println("  # Generate a migration recipe for the starters of a library")
+	fmt.Println("  rewrite-spring-go generate-recipes -name com.example.MigrateAcmeStarterProperties \\")
+	fmt.Println("    -output rewrite.yml ./acme-starter/build/libs/acme-starter-2.0.0.jar")
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/recipes"
+	"github.com/openrewrite/rewrite-spring-go/pkg/runner"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// runLint implements the lint command, which validates Spring configuration
+// files against configuration metadata
+func runLint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	var (
+		sourcePath  = flags.String("source", "", "Source directory to process")
+		outputPath  = flags.String("output", "", "Output directory for fixed files (optional, defaults to source)")
+		metadataStr = flags.String("metadata", "", "Comma-separated jars, directories or metadata JSON files")
+		fix         = flags.Bool("fix", false, "Rename deprecated properties that have a replacement")
+		strict      = flags.Bool("strict", false, "Also report unknown keys outside the namespaces of the metadata")
+		patternsStr = flags.String("patterns", "", "Comma-separated list of file patterns")
+		allConfig   = flags.Bool("include-non-spring-config", false, "Also check properties/YAML files that are not Spring configuration")
+		embedded    = flags.String("embedded-config-paths", "", "Comma-separated JSONPaths of YAML keys holding Spring configuration")
+		dryRun      = flags.Bool("dry-run", false, "Show what -fix would change without modifying files")
+		backup      = flags.Bool("backup", true, "Create backup files before modifying")
+		debug       = flags.Bool("debug", false, "Enable debug logging")
+		help        = flags.Bool("help", false, "Show help")
+	)
+	flags.Parse(args)
+
+	if *help {
+		showLintHelp()
+		return
+	}
+
+	if *sourcePath == "" {
+		fmt.Fprintf(os.Stderr, "Error: source path is required\n")
+		os.Exit(1)
+	}
+
+	metadataPaths := splitList(*metadataStr)
+	if len(metadataPaths) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: metadata is required\n")
+		os.Exit(1)
+	}
+
+	embeddedPaths := splitList(*embedded)
+	for _, path := range embeddedPaths {
+		if _, err := utils.ParseJSONPath(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *outputPath == "" {
+		*outputPath = *sourcePath
+	}
+
+	logger := core.NewConsoleLogger(*debug)
+
+	metadata, err := utils.LoadConfigurationMetadata(metadataPaths)
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+	logger.Debug("Loaded %d properties from configuration metadata", len(metadata.Properties))
+	if err := utils.LoadEnumConstants(metadata, metadataPaths, logger); err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+	logger.Debug("Loaded the constants of %d enum types", len(metadata.Enums))
+
+	validate := recipes.NewValidateSpringPropertiesRecipe(utils.NewMetadataIndex(metadata))
+	validate.Fix = *fix
+	validate.Strict = *strict
+	validate.IncludeNonSpringConfig = *allConfig
+
+	fileRunner := runner.NewRunner(*sourcePath, logger)
+	fileRunner.OutputPath = *outputPath
+	fileRunner.DryRun = *dryRun
+	fileRunner.Backup = *backup
+	if patterns := splitList(*patternsStr); len(patterns) > 0 {
+		fileRunner.Patterns = patterns
+	}
+	fileRunner.EmbeddedConfigPaths = embeddedPaths
+
+	result, err := fileRunner.Run(context.Background(), validate)
+	if err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+
+	errors := printFindings(result.Findings)
+	if *fix {
+		logger.Info("%d files fixed", len(result.Modified))
+	}
+	logger.Info("%d problems (%d errors, %d warnings)", len(result.Findings), errors, len(result.Findings)-errors)
+	if errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// printFindings prints findings by file and line, returning the number of errors
+func printFindings(findings []core.Finding) int {
+	sorted := append([]core.Finding(nil), findings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+
+	errors := 0
+	for _, finding := range sorted {
+		if finding.Severity == core.Error {
+			errors++
+		}
+		fmt.Printf("%s:%d: %s: %s\n", finding.Path, finding.Line, finding.Severity, finding.Message)
+	}
+	return errors
+}
+
+func showLintHelp() {
+	fmt.Println("rewrite-spring-go lint - Validate Spring configuration against configuration metadata")
+	fmt.Println()
+	fmt.Println("USAGE:")
+	fmt.Println("  rewrite-spring-go lint -source DIR -metadata PATH[,PATH...] [OPTIONS]")
+	fmt.Println()
+	fmt.Println("  Reports unknown keys, deprecated keys, values that cannot be converted to the")
+	fmt.Println("  property type, invalid enum values and values outside the documented hints, as")
+	fmt.Println("  path:line: severity: message. Exits with status 1 when there are errors.")
+	fmt.Println()
+	fmt.Println("  Enum constants are read from the classes of the jars and directories given as")
+	fmt.Println("  -metadata, so pass the jars declaring the enum types (e.g. spring-boot.jar for")
+	fmt.Println("  server.shutdown) along with the metadata. Invalid enum values are errors; values")
+	fmt.Println("  of enums whose class is not found, such as JDK enums, only get a warning when")
+	fmt.Println("  they are not among the documented hints.")
+	fmt.Println()
+	fmt.Println("OPTIONS:")
+	fmt.Println("  -source string")
+	fmt.Println("        Source directory to process (required)")
+	fmt.Println("  -metadata string")
+	fmt.Println("        Comma-separated jars, directories searched for jars and metadata files,")
+	fmt.Println("        or spring-configuration-metadata.json files (required)")
+	fmt.Println("  -fix")
+	fmt.Println("        Rename deprecated properties to the replacement the metadata names")
+	fmt.Println("  -strict")
+	fmt.Println("        Also report unknown keys outside the namespaces of the metadata, such as")
+	fmt.Println("        application properties under \"app\"")
+	fmt.Println("  -output string")
+	fmt.Println("        Output directory for fixed files (optional, defaults to source)")
+	fmt.Println("  -patterns string")
+	fmt.Println("        Comma-separated list of file patterns")
+	fmt.Println("  -include-non-spring-config")
+	fmt.Println("        Also check properties/YAML files that are not Spring configuration")
+	fmt.Println("  -embedded-config-paths string")
+	fmt.Println("        Comma-separated JSONPaths such as '$.app.config' of YAML keys holding Spring")
+	fmt.Println("        configuration")
+	fmt.Println("  -dry-run")
+	fmt.Println("        Show what -fix would change without modifying files")
+	fmt.Println("  -backup")
+	fmt.Println("        Create backup files before modifying (default: true)")
+	fmt.Println("  -debug")
+	fmt.Println("        Enable debug logging")
+	fmt.Println("  -help")
+	fmt.Println("        Show this help message")
+	fmt.Println()
+	fmt.Println("EXAMPLES:")
+	fmt.Println("  # Check a project against the metadata of the jars it depends on")
+	fmt.Println("  rewrite-spring-go lint -source ./myproject -metadata ./build/dependencies")
+	fmt.Println()
+	fmt.Println("  # Replace deprecated properties")
+	fmt.Println("  rewrite-spring-go lint -source ./myproject -metadata ./libs/starter.jar -fix")
+}
@@ -6,25 +6,43 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/openrewrite/rewrite-spring-go/pkg/core"
 	"github.com/openrewrite/rewrite-spring-go/pkg/recipes"
+	"github.com/openrewrite/rewrite-spring-go/pkg/runner"
 	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "generate-recipes":
+			runGenerateRecipes(os.Args[2:])
+			return
+		case "lint":
+			runLint(os.Args[2:])
+			return
+		}
+	}
+
 	var (
 		sourcePath  = flag.String("source", "", "Source directory to process")
 		outputPath  = flag.String("output", "", "Output directory (optional, defaults to source)")
-		recipe      = flag.String("recipe", "", "Recipe to apply (change-property-key, add-property)")
+		recipe      = flag.String("recipe", "", "Recipe to apply (change-property-key, change-property-value, comment-out-property, delete-property, add-property, expand-properties, properties-to-kebab-case, separate-yaml-by-profile, merge-bootstrap-yaml, properties-to-yaml, yaml-to-properties, find-secrets, externalize-secrets)")
 		oldKey      = flag.String("old-key", "", "Old property key (for change-property-key)")
 		newKey      = flag.String("new-key", "", "New property key (for change-property-key)")
-		property    = flag.String("property", "", "Property key (for add-property)")
-		value       = flag.String("value", "", "Property value (for add-property)")
+		property    = flag.String("property", "", "Property key (for add-property, change-property-value, comment-out-property, delete-property)")
+		value       = flag.String("value", "", "Property value (for add-property, change-property-value)")
+		oldValue    = flag.String("old-value", "", "Only change values equal to this (for change-property-value)")
+		regex       = flag.Bool("regex", false, "Treat -old-value (change-property-value) or -old-key and -except (change-property-key) as regular expressions")
 		comment     = flag.String("comment", "", "Comment for the property (optional)")
 		exceptStr   = flag.String("except", "", "Comma-separated list of exceptions")
 		patternsStr = flag.String("patterns", "", "Comma-separated list of file patterns")
+		allConfig   = flag.Bool("include-non-spring-config", false, "Also modify properties/YAML files that are not Spring configuration")
+		embedded    = flag.String("embedded-config-paths", "", "Comma-separated JSONPaths of YAML keys holding Spring configuration")
+		envFile     = flag.String("env-file", ".env", "File receiving externalized secrets, relative to the source directory (for externalize-secrets)")
 		dryRun      = flag.Bool("dry-run", false, "Show what would be changed without modifying files")
 		backup      = flag.Bool("backup", true, "Create backup files before modifying")
 		debug       = flag.Bool("debug", false, "Enable debug logging")
@@ -59,21 +77,15 @@ func main() {
 	// Create execution context
 	ctx := context.Background()
 
-	// Parse exceptions
-	var except []string
-	if *exceptStr != "" {
-		except = strings.Split(*exceptStr, ",")
-		for i, ex := range except {
-			except[i] = strings.TrimSpace(ex)
-		}
-	}
+	except := splitList(*exceptStr)
+	patterns := splitList(*patternsStr)
 
-	// Parse patterns
-	var patterns []string
-	if *patternsStr != "" {
-		patterns = strings.Split(*patternsStr, ",")
-		for i, pattern := range patterns {
-			patterns[i] = strings.TrimSpace(pattern)
+	// Parse embedded configuration paths
+	embeddedPaths := splitList(*embedded)
+	for _, path := range embeddedPaths {
+		if _, err := utils.ParseJSONPath(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 	}
 
@@ -85,13 +97,90 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: old-key and new-key are required for change-property-key recipe\n")
 			os.Exit(1)
 		}
-		recipeInstance = recipes.NewChangeSpringPropertyKeyRecipe(*oldKey, *newKey, except)
+		for _, pattern := range append([]string{*oldKey}, except...) {
+			if _, err := utils.CompileKeyPattern(pattern, *regex); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		changeKey := recipes.NewChangeSpringPropertyKeyRecipe(*oldKey, *newKey, except)
+		changeKey.Regex = *regex
+		changeKey.IncludeNonSpringConfig = *allConfig
+		recipeInstance = changeKey
+	case "change-property-value":
+		if *property == "" && *oldValue == "" {
+			fmt.Fprintf(os.Stderr, "Error: property or old-value is required for change-property-value recipe\n")
+			os.Exit(1)
+		}
+		if *regex && *oldValue == "" {
+			fmt.Fprintf(os.Stderr, "Error: old-value is required when regex is enabled\n")
+			os.Exit(1)
+		}
+		if *regex {
+			if _, err := regexp.Compile(*oldValue); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid old-value pattern: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		changeValue := recipes.NewChangeSpringPropertyValueRecipe(*property, *value, *oldValue, *regex)
+		changeValue.IncludeNonSpringConfig = *allConfig
+		recipeInstance = changeValue
+	case "comment-out-property":
+		if *property == "" || *comment == "" {
+			fmt.Fprintf(os.Stderr, "Error: property and comment are required for comment-out-property recipe\n")
+			os.Exit(1)
+		}
+		commentOut := recipes.NewCommentOutSpringPropertyKeyRecipe(*property, *comment)
+		commentOut.IncludeNonSpringConfig = *allConfig
+		recipeInstance = commentOut
+	case "delete-property":
+		if *property == "" {
+			fmt.Fprintf(os.Stderr, "Error: property is required for delete-property recipe\n")
+			os.Exit(1)
+		}
+		deleteProperty := recipes.NewDeleteSpringPropertyRecipe(*property)
+		deleteProperty.IncludeNonSpringConfig = *allConfig
+		recipeInstance = deleteProperty
 	case "add-property":
 		if *property == "" || *value == "" {
 			fmt.Fprintf(os.Stderr, "Error: property and value are required for add-property recipe\n")
 			os.Exit(1)
 		}
-		recipeInstance = recipes.NewAddSpringPropertyRecipe(*property, *value, *comment, patterns)
+		addProperty := recipes.NewAddSpringPropertyRecipe(*property, *value, *comment, patterns)
+		addProperty.IncludeNonSpringConfig = *allConfig
+		recipeInstance = addProperty
+	case "expand-properties":
+		expand := recipes.NewExpandPropertiesRecipe("")
+		expand.IncludeNonSpringConfig = *allConfig
+		recipeInstance = expand
+	case "properties-to-kebab-case":
+		kebabCase := recipes.NewPropertiesToKebabCaseRecipe()
+		kebabCase.IncludeNonSpringConfig = *allConfig
+		recipeInstance = kebabCase
+	case "separate-yaml-by-profile":
+		recipeInstance = recipes.NewSeparateApplicationYamlByProfileRecipe()
+	case "merge-bootstrap-yaml":
+		recipeInstance = recipes.NewMergeBootstrapYamlWithApplicationYamlRecipe()
+	case "properties-to-yaml":
+		propertiesToYaml := recipes.NewPropertiesToYamlRecipe()
+		propertiesToYaml.IncludeNonSpringConfig = *allConfig
+		recipeInstance = propertiesToYaml
+	case "yaml-to-properties":
+		yamlToProperties := recipes.NewYamlToPropertiesRecipe()
+		yamlToProperties.IncludeNonSpringConfig = *allConfig
+		recipeInstance = yamlToProperties
+	case "find-secrets":
+		findSecrets := recipes.NewFindSecretsRecipe()
+		findSecrets.IncludeNonSpringConfig = *allConfig
+		recipeInstance = findSecrets
+	case "externalize-secrets":
+		secretsFile := *envFile
+		if !filepath.IsAbs(secretsFile) {
+			secretsFile = filepath.Join(*sourcePath, secretsFile)
+		}
+		externalize := recipes.NewExternalizeSecretsRecipe(secretsFile)
+		externalize.IncludeNonSpringConfig = *allConfig
+		recipeInstance = externalize
 	default:
 		fmt.Fprintf(os.Stderr, "Error: unknown recipe '%s'\n", *recipe)
 		os.Exit(1)
@@ -102,96 +191,65 @@ func main() {
 	logger.Debug("Source: %s", *sourcePath)
 	logger.Debug("Output: %s", *outputPath)
 
-	// Find configuration files
-	defaultPatterns := []string{
-		"**/application.properties",
-		"**/application.yml",
-		"**/application.yaml",
-		"**/*.java",
-	}
-
-	if len(patterns) == 0 {
-		patterns = defaultPatterns
+	fileRunner := runner.NewRunner(*sourcePath, logger)
+	fileRunner.OutputPath = *outputPath
+	fileRunner.DryRun = *dryRun
+	fileRunner.Backup = *backup
+	if len(patterns) > 0 {
+		fileRunner.Patterns = patterns
 	}
+	fileRunner.EmbeddedConfigPaths = embeddedPaths
 
-	configFiles, err := utils.FindSpringConfigFiles(*sourcePath, patterns)
+	result, err := fileRunner.Run(ctx, recipeInstance)
 	if err != nil {
-		logger.Error("Failed to find configuration files: %v", err)
+		logger.Error("%v", err)
 		os.Exit(1)
 	}
 
-	logger.Info("Found %d configuration files", len(configFiles))
-
-	// Process each file
-	modifiedCount := 0
-	for _, filePath := range configFiles {
-		logger.Debug("Processing file: %s", filePath)
-
-		// Load source file
-		sourceFile, err := utils.LoadSourceFile(filePath)
-		if err != nil {
-			logger.Error("Failed to load file %s: %v", filePath, err)
-			continue
-		}
-
-		// Apply recipe
-		originalContent := sourceFile.GetContent()
-		transformedFile, err := recipeInstance.Apply(ctx, sourceFile)
-		if err != nil {
-			logger.Error("Failed to apply recipe to %s: %v", filePath, err)
-			continue
-		}
-
-		// Check if file was modified
-		if transformedFile.GetContent() != originalContent {
-			modifiedCount++
-			logger.Info("Modified: %s", filePath)
-
-			if *dryRun {
-				logger.Info("DRY RUN: Would modify file %s", filePath)
-				continue
-			}
-
-			// Create backup if requested
-			if *backup {
-				if err := utils.BackupFile(filePath); err != nil {
-					logger.Warn("Failed to create backup for %s: %v", filePath, err)
-				}
-			}
-
-			// Determine output file path
-			outputFilePath := filePath
-			if *outputPath != *sourcePath {
-				relPath, err := filepath.Rel(*sourcePath, filePath)
-				if err != nil {
-					logger.Error("Failed to get relative path for %s: %v", filePath, err)
-					continue
-				}
-				outputFilePath = filepath.Join(*outputPath, relPath)
-			}
-
-			// Save transformed file
-			if err := utils.SaveSourceFile(transformedFile, outputFilePath); err != nil {
-				logger.Error("Failed to save file %s: %v", outputFilePath, err)
-				continue
-			}
-
-			logger.Debug("Saved transformed file: %s", outputFilePath)
-		}
-	}
+	errors := printFindings(result.Findings)
 
 	if *dryRun {
-		logger.Info("DRY RUN completed. %d files would be modified", modifiedCount)
+		logger.Info("DRY RUN completed. %d files would be modified, %d created, %d deleted",
+			len(result.Modified), len(result.Generated), len(result.Deleted))
 	} else {
-		logger.Info("Processing completed. %d files modified", modifiedCount)
+		logger.Info("Processing completed. %d files modified, %d created, %d deleted",
+			len(result.Modified), len(result.Generated), len(result.Deleted))
+	}
+	if len(result.Findings) > 0 {
+		logger.Info("%d problems (%d errors, %d warnings)", len(result.Findings), errors, len(result.Findings)-errors)
+	}
+	if errors > 0 {
+		os.Exit(1)
 	}
 }
 
+// splitList splits a comma-separated flag value into its trimmed items
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
 func showHelp() {
 	fmt.Println("rewrite-spring-go - Spring configuration transformation tool")
 	fmt.Println()
 	fmt.Println("USAGE:")
 	fmt.Println("  rewrite-spring-go [OPTIONS]")
+	fmt.Println("  rewrite-spring-go generate-recipes [OPTIONS] PATH...")
+	fmt.Println("  rewrite-spring-go lint -source DIR -metadata PATH[,PATH...] [OPTIONS]")
+	fmt.Println()
+	fmt.Println("COMMANDS:")
+	fmt.Println("  generate-recipes")
+	fmt.Println("        Generate a migration recipe from the deprecations in the configuration")
+	fmt.Println("        metadata of jars or directories; see 'generate-recipes -help'")
+	fmt.Println("  lint")
+	fmt.Println("        Validate Spring configuration against configuration metadata, optionally")
+	fmt.Println("        replacing deprecated properties; see 'lint -help'")
 	fmt.Println()
 	fmt.Println("OPTIONS:")
 	fmt.Println("  -source string")
@@ -199,21 +257,46 @@ func showHelp() {
 	fmt.Println("  -output string")
 	fmt.Println("        Output directory (optional, defaults to source)")
 	fmt.Println("  -recipe string")
-	fmt.Println("        Recipe to apply: change-property-key, add-property (required)")
+	fmt.Println("        Recipe to apply: change-property-key, change-property-value,")
+	fmt.Println("        comment-out-property, delete-property, add-property,")
+	fmt.Println("        expand-properties, properties-to-kebab-case,")
+	fmt.Println("        separate-yaml-by-profile, merge-bootstrap-yaml,")
+	fmt.Println("        properties-to-yaml, yaml-to-properties, find-secrets,")
+	fmt.Println("        externalize-secrets (required)")
 	fmt.Println("  -old-key string")
-	fmt.Println("        Old property key (required for change-property-key)")
+	fmt.Println("        Old property key glob (required for change-property-key); \"*\" matches one")
+	fmt.Println("        segment, not its list index, \"**\" several, and \"(*)\" captures a segment")
+	fmt.Println("        for -new-key; \"[*]\" matches any list index, which a \"[*]\" in -new-key keeps")
 	fmt.Println("  -new-key string")
-	fmt.Println("        New property key (required for change-property-key)")
+	fmt.Println("        New property key (required for change-property-key); $1, $2... refer")
+	fmt.Println("        to the captured segments")
 	fmt.Println("  -property string")
-	fmt.Println("        Property key (required for add-property)")
+	fmt.Println("        Property key (required for add-property and comment-out-property;")
+	fmt.Println("        key glob for change-property-value and delete-property)")
 	fmt.Println("  -value string")
-	fmt.Println("        Property value (required for add-property)")
+	fmt.Println("        Property value (required for add-property; new value for change-property-value);")
+	fmt.Println("        add-property adds a list or map for a YAML flow value such as \"[a, b]\"")
+	fmt.Println("  -old-value string")
+	fmt.Println("        Only change values equal to this (for change-property-value)")
+	fmt.Println("  -regex")
+	fmt.Println("        Treat -old-value as a regular expression matching the whole value, using $1, $2... in -value;")
+	fmt.Println("        for change-property-key, treat -old-key and -except as regular expressions")
 	fmt.Println("  -comment string")
-	fmt.Println("        Comment for the property (optional)")
+	fmt.Println("        Comment for the property (optional; required for comment-out-property)")
 	fmt.Println("  -except string")
-	fmt.Println("        Comma-separated list of exceptions")
+	fmt.Println("        Comma-separated subkey patterns below -old-key to leave unchanged")
 	fmt.Println("  -patterns string")
 	fmt.Println("        Comma-separated list of file patterns")
+	fmt.Println("  -include-non-spring-config")
+	fmt.Println("        Also modify properties/YAML files that are not Spring configuration")
+	fmt.Println("        (i18n bundles, build and CI files); by default only Spring config is changed")
+	fmt.Println("  -embedded-config-paths string")
+	fmt.Println("        Comma-separated JSONPaths such as '$.app.config' of YAML keys holding Spring")
+	fmt.Println("        configuration, e.g. in Helm values; application.yml/properties blocks of")
+	fmt.Println("        ConfigMaps are found without them")
+	fmt.Println("  -env-file string")
+	fmt.Println("        File receiving the secrets moved by externalize-secrets, relative to the")
+	fmt.Println("        source directory; it is added to the .gitignore next to it (default: .env)")
 	fmt.Println("  -dry-run")
 	fmt.Println("        Show what would be changed without modifying files")
 	fmt.Println("  -backup")
@@ -226,14 +309,43 @@ func showHelp() {
 	fmt.Println("EXAMPLES:")
 	fmt.Println("  # Change property key in all Spring config files")
 	fmt.Println("  rewrite-spring-go -source ./myproject -recipe change-property-key \\")
-	fmt.Println("    -old-key management.metrics.binders.*.enabled \\")
-	fmt.Println("    -new-key management.metrics.enable.process.files")
+	fmt.Println("    -old-key 'management.metrics.export.(*).enabled' \\")
+	fmt.Println("    -new-key 'management.$1.metrics.export.enabled'")
+	fmt.Println()
+	fmt.Println("  # Change a property value")
+	fmt.Println("  rewrite-spring-go -source ./myproject -recipe change-property-value \\")
+	fmt.Println("    -property server.error.include-stacktrace -old-value on-trace-param -value on-param")
+	fmt.Println()
+	fmt.Println("  # Comment out a property that was removed")
+	fmt.Println("  rewrite-spring-go -source ./myproject -recipe comment-out-property \\")
+	fmt.Println("    -property management.metrics.binders -comment \"Property removed in Spring Boot 3.0\"")
+	fmt.Println()
+	fmt.Println("  # Delete properties matching a key glob")
+	fmt.Println("  rewrite-spring-go -source ./myproject -recipe delete-property \\")
+	fmt.Println("    -property 'management.endpoint.configprops.*'")
 	fmt.Println()
 	fmt.Println("  # Add a new property to Spring config files")
 	fmt.Println("  rewrite-spring-go -source ./myproject -recipe add-property \\")
 	fmt.Println("    -property server.port -value 8080 -comment \"Server port configuration\"")
 	fmt.Println()
+	fmt.Println("  # Add a list property")
+	fmt.Println("  rewrite-spring-go -source ./myproject -recipe add-property \\")
+	fmt.Println("    -property spring.profiles.include -value \"[metrics, tracing]\"")
+	fmt.Println()
+	fmt.Println("  # Rename a key in Spring configuration nested in Helm values")
+	fmt.Println("  rewrite-spring-go -source ./chart -recipe change-property-key \\")
+	fmt.Println("    -old-key spring.redis.host -new-key spring.data.redis.host \\")
+	fmt.Println("    -embedded-config-paths '$.app.config'")
+	fmt.Println()
+	fmt.Println("  # Report plaintext secrets, then move them to a gitignored .env file")
+	fmt.Println("  rewrite-spring-go -source ./myproject -recipe find-secrets")
+	fmt.Println("  rewrite-spring-go -source ./myproject -recipe externalize-secrets")
+	fmt.Println()
 	fmt.Println("  # Dry run to see what would be changed")
 	fmt.Println("  rewrite-spring-go -source ./myproject -recipe change-property-key \\")
 	fmt.Println("    -old-key old.property -new-key new.property -dry-run")
+	fmt.Println()
+	fmt.Println("  # Generate a migration recipe from the metadata of a starter jar")
+	fmt.Println("  rewrite-spring-go generate-recipes -name com.example.MigrateStarterProperties \\")
+	fmt.Println("    -output rewrite.yml ./starter.jar")
 }
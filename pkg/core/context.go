@@ -0,0 +1,37 @@
+package core
+
+import (
+	"context"
+)
+
+// executionContextKey is the context.Context key holding the ExecutionContext
+type executionContextKey struct{}
+
+// WithExecutionContext returns a context carrying the execution context for recipes
+func WithExecutionContext(ctx context.Context, executionContext *ExecutionContext) context.Context {
+	return context.WithValue(ctx, executionContextKey{}, executionContext)
+}
+
+// GetExecutionContext returns the execution context carried by ctx, or an empty
+// one with a NullLogger when there is none
+func GetExecutionContext(ctx context.Context) *ExecutionContext {
+	if executionContext, ok := ctx.Value(executionContextKey{}).(*ExecutionContext); ok && executionContext != nil {
+		if executionContext.Logger == nil {
+			executionContext.Logger = NewNullLogger()
+		}
+		return executionContext
+	}
+	return &ExecutionContext{Options: map[string]interface{}{}, Logger: NewNullLogger()}
+}
+
+// Report records a finding at a line index of sourceFile. Findings in embedded
+// configuration are reported at the corresponding line of the host file.
+func (c *ExecutionContext) Report(sourceFile SourceFile, line int, finding Finding) {
+	finding.Path = sourceFile.GetPath()
+	if embedded, ok := sourceFile.(*EmbeddedSourceFile); ok {
+		finding.Path = embedded.Host.GetPath()
+		line += embedded.Line
+	}
+	finding.Line = line + 1
+	c.Findings = append(c.Findings, finding)
+}
@@ -0,0 +1,278 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charset identifies the character encoding used to store a source file on disk
+type Charset int
+
+const (
+	UTF8 Charset = iota
+	ISO88591
+	UTF16LE
+	UTF16BE
+	UTF32LE
+	UTF32BE
+)
+
+// String returns the canonical name of the charset
+func (c Charset) String() string {
+	switch c {
+	case ISO88591:
+		return "ISO-8859-1"
+	case UTF16LE:
+		return "UTF-16LE"
+	case UTF16BE:
+		return "UTF-16BE"
+	case UTF32LE:
+		return "UTF-32LE"
+	case UTF32BE:
+		return "UTF-32BE"
+	default:
+		return "UTF-8"
+	}
+}
+
+// Line ending conventions recognised in source files
+const (
+	LF   = "\n"
+	CRLF = "\r\n"
+	CR   = "\r"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+	utf32LEBOM = []byte{0xFF, 0xFE, 0x00, 0x00}
+	utf32BEBOM = []byte{0x00, 0x00, 0xFE, 0xFF}
+)
+
+// Encoding describes how the text of a source file is laid out on disk.
+// Recipes always see content with "\n" line endings and no BOM; the
+// encoding is applied again when the file is written back.
+type Encoding struct {
+	Charset    Charset
+	BOM        bool
+	LineEnding string
+}
+
+// DefaultEncoding is used for files that are created from scratch
+var DefaultEncoding = Encoding{Charset: UTF8, LineEnding: LF}
+
+// DecodeContent detects the charset, BOM and dominant line ending of raw file
+// bytes and returns the decoded text normalised to "\n" line endings
+func DecodeContent(data []byte, fileType FileType) (string, Encoding) {
+	enc := Encoding{Charset: UTF8}
+
+	var text string
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		enc.BOM = true
+		text = string(data[len(utf8BOM):])
+	case bytes.HasPrefix(data, utf32LEBOM):
+		// Checked before UTF-16LE, whose BOM it starts with
+		enc.Charset = UTF32LE
+		enc.BOM = true
+		text = decodeUTF32(data[len(utf32LEBOM):], false)
+	case bytes.HasPrefix(data, utf32BEBOM):
+		enc.Charset = UTF32BE
+		enc.BOM = true
+		text = decodeUTF32(data[len(utf32BEBOM):], true)
+	case bytes.HasPrefix(data, utf16LEBOM):
+		enc.Charset = UTF16LE
+		enc.BOM = true
+		text = decodeUTF16(data[len(utf16LEBOM):], false)
+	case bytes.HasPrefix(data, utf16BEBOM):
+		enc.Charset = UTF16BE
+		enc.BOM = true
+		text = decodeUTF16(data[len(utf16BEBOM):], true)
+	case !utf8.Valid(data):
+		enc.Charset = ISO88591
+		text = decodeLatin1(data)
+	default:
+		// Properties files are read as ISO-8859-1 by Java unless stated otherwise,
+		// so plain ASCII files keep that convention when new characters are added
+		if fileType == Properties && isASCII(data) {
+			enc.Charset = ISO88591
+		}
+		text = string(data)
+	}
+
+	enc.LineEnding = DetectLineEnding(text)
+	return NormalizeLineEndings(text), enc
+}
+
+// EncodeContent converts "\n"-normalised text back to bytes using the given encoding
+func EncodeContent(content string, enc Encoding, fileType FileType) ([]byte, error) {
+	lineEnding := enc.LineEnding
+	if lineEnding == "" {
+		lineEnding = LF
+	}
+	if lineEnding != LF {
+		content = strings.ReplaceAll(content, LF, lineEnding)
+	}
+
+	var buf bytes.Buffer
+	switch enc.Charset {
+	case ISO88591:
+		for _, r := range content {
+			switch {
+			case r <= 0xFF:
+				buf.WriteByte(byte(r))
+			case fileType == Properties:
+				writeUnicodeEscape(&buf, r)
+			default:
+				return nil, fmt.Errorf("character %q cannot be encoded as %s", r, enc.Charset)
+			}
+		}
+	case UTF16LE, UTF16BE:
+		bigEndian := enc.Charset == UTF16BE
+		if enc.BOM {
+			if bigEndian {
+				buf.Write(utf16BEBOM)
+			} else {
+				buf.Write(utf16LEBOM)
+			}
+		}
+		for _, u := range utf16.Encode([]rune(content)) {
+			if bigEndian {
+				buf.WriteByte(byte(u >> 8))
+				buf.WriteByte(byte(u))
+			} else {
+				buf.WriteByte(byte(u))
+				buf.WriteByte(byte(u >> 8))
+			}
+		}
+	case UTF32LE, UTF32BE:
+		bigEndian := enc.Charset == UTF32BE
+		if enc.BOM {
+			if bigEndian {
+				buf.Write(utf32BEBOM)
+			} else {
+				buf.Write(utf32LEBOM)
+			}
+		}
+		for _, r := range content {
+			unit := make([]byte, 4)
+			if bigEndian {
+				binary.BigEndian.PutUint32(unit, uint32(r))
+			} else {
+				binary.LittleEndian.PutUint32(unit, uint32(r))
+			}
+			buf.Write(unit)
+		}
+	default:
+		if enc.BOM {
+			buf.Write(utf8BOM)
+		}
+		buf.WriteString(content)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// HasUnicodeBOM reports whether data starts with the byte order mark of one of
+// the Unicode charsets DecodeContent reads
+func HasUnicodeBOM(data []byte) bool {
+	for _, bom := range [][]byte{utf8BOM, utf16LEBOM, utf16BEBOM, utf32BEBOM} {
+		if bytes.HasPrefix(data, bom) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectLineEnding returns the most common line ending in the text, defaulting to LF
+func DetectLineEnding(text string) string {
+	crlf, lf, cr := 0, 0, 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\r':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				crlf++
+				i++
+			} else {
+				cr++
+			}
+		case '\n':
+			lf++
+		}
+	}
+
+	switch {
+	case crlf > lf && crlf >= cr:
+		return CRLF
+	case cr > lf && cr > crlf:
+		return CR
+	default:
+		return LF
+	}
+}
+
+// NormalizeLineEndings converts CRLF and CR line endings to LF
+func NormalizeLineEndings(text string) string {
+	if !strings.Contains(text, CR) {
+		return text
+	}
+	text = strings.ReplaceAll(text, CRLF, LF)
+	return strings.ReplaceAll(text, CR, LF)
+}
+
+// decodeLatin1 maps every byte to the code point of the same value
+func decodeLatin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// decodeUTF16 decodes UTF-16 code units in the given byte order
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodeUTF32 decodes UTF-32 code units in the given byte order
+func decodeUTF32(data []byte, bigEndian bool) string {
+	runes := make([]rune, 0, len(data)/4)
+	for i := 0; i+3 < len(data); i += 4 {
+		if bigEndian {
+			runes = append(runes, rune(binary.BigEndian.Uint32(data[i:])))
+		} else {
+			runes = append(runes, rune(binary.LittleEndian.Uint32(data[i:])))
+		}
+	}
+	return string(runes)
+}
+
+// writeUnicodeEscape writes r as one or two \uXXXX escapes, as used in properties files
+func writeUnicodeEscape(buf *bytes.Buffer, r rune) {
+	for _, u := range utf16.Encode([]rune{r}) {
+		fmt.Fprintf(buf, "\\u%04X", u)
+	}
+}
+
+// isASCII reports whether data only contains 7-bit characters
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
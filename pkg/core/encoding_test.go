@@ -0,0 +1,169 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		fileType FileType
+		content  string
+		encoding Encoding
+	}{
+		{
+			name:     "UTF-8",
+			data:     []byte("name: caf\xc3\xa9\n"),
+			fileType: YAML,
+			content:  "name: café\n",
+			encoding: Encoding{Charset: UTF8, LineEnding: LF},
+		},
+		{
+			name:     "UTF-8 with BOM",
+			data:     []byte("\xef\xbb\xbfname: app\n"),
+			fileType: YAML,
+			content:  "name: app\n",
+			encoding: Encoding{Charset: UTF8, BOM: true, LineEnding: LF},
+		},
+		{
+			name:     "UTF-16LE with BOM",
+			data:     []byte("\xff\xfea\x00=\x00\xe9\x00\n\x00"),
+			fileType: Properties,
+			content:  "a=é\n",
+			encoding: Encoding{Charset: UTF16LE, BOM: true, LineEnding: LF},
+		},
+		{
+			name:     "UTF-16BE with BOM",
+			data:     []byte("\xfe\xff\x00a\x00=\xd8\x3d\xde\x00\x00\n"),
+			fileType: Properties,
+			content:  "a=😀\n",
+			encoding: Encoding{Charset: UTF16BE, BOM: true, LineEnding: LF},
+		},
+		{
+			name:     "UTF-32LE with BOM",
+			data:     []byte("\xff\xfe\x00\x00a\x00\x00\x00\x00\xf6\x01\x00\n\x00\x00\x00"),
+			fileType: PlainText,
+			content:  "a😀\n",
+			encoding: Encoding{Charset: UTF32LE, BOM: true, LineEnding: LF},
+		},
+		{
+			name:     "UTF-32BE with BOM",
+			data:     []byte("\x00\x00\xfe\xff\x00\x00\x00a\x00\x00\x00\n"),
+			fileType: PlainText,
+			content:  "a\n",
+			encoding: Encoding{Charset: UTF32BE, BOM: true, LineEnding: LF},
+		},
+		{
+			name:     "ISO-8859-1",
+			data:     []byte("a=caf\xe9\n"),
+			fileType: Properties,
+			content:  "a=café\n",
+			encoding: Encoding{Charset: ISO88591, LineEnding: LF},
+		},
+		{
+			name:     "ASCII properties default to ISO-8859-1",
+			data:     []byte("a=1\n"),
+			fileType: Properties,
+			content:  "a=1\n",
+			encoding: Encoding{Charset: ISO88591, LineEnding: LF},
+		},
+		{
+			name:     "ASCII YAML stays UTF-8",
+			data:     []byte("a: 1\n"),
+			fileType: YAML,
+			content:  "a: 1\n",
+			encoding: Encoding{Charset: UTF8, LineEnding: LF},
+		},
+		{
+			name:     "CRLF",
+			data:     []byte("a=1\r\nb=2\r\n"),
+			fileType: Properties,
+			content:  "a=1\nb=2\n",
+			encoding: Encoding{Charset: ISO88591, LineEnding: CRLF},
+		},
+		{
+			name:     "CR",
+			data:     []byte("a=1\rb=2\r"),
+			fileType: Properties,
+			content:  "a=1\nb=2\n",
+			encoding: Encoding{Charset: ISO88591, LineEnding: CR},
+		},
+		{
+			name:     "UTF-16LE with CRLF",
+			data:     []byte("\xff\xfea\x00\r\x00\n\x00"),
+			fileType: YAML,
+			content:  "a\n",
+			encoding: Encoding{Charset: UTF16LE, BOM: true, LineEnding: CRLF},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, encoding := DecodeContent(tt.data, tt.fileType)
+			if content != tt.content {
+				t.Errorf("DecodeContent() content = %q, want %q", content, tt.content)
+			}
+			if encoding != tt.encoding {
+				t.Errorf("DecodeContent() encoding = %+v, want %+v", encoding, tt.encoding)
+			}
+			data, err := EncodeContent(content, encoding, tt.fileType)
+			if err != nil {
+				t.Fatalf("EncodeContent() error = %v", err)
+			}
+			if !bytes.Equal(data, tt.data) {
+				t.Errorf("EncodeContent() = %q, want %q", data, tt.data)
+			}
+		})
+	}
+}
+
+func TestEncodeContentBeyondLatin1(t *testing.T) {
+	latin1 := Encoding{Charset: ISO88591, LineEnding: LF}
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"Latin-1 characters are written as bytes", "a=é\n", "a=\xe9\n"},
+		{"other characters are escaped", "a=✓\n", "a=\\u2713\n"},
+		{"supplementary characters are escaped as surrogate pairs", "a=😀\n", "a=\\uD83D\\uDE00\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := EncodeContent(tt.content, latin1, Properties)
+			if err != nil {
+				t.Fatalf("EncodeContent() error = %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("EncodeContent() = %q, want %q", data, tt.expected)
+			}
+		})
+	}
+
+	if _, err := EncodeContent("a: ✓\n", latin1, YAML); err == nil {
+		t.Error("EncodeContent() of a YAML file beyond ISO-8859-1 error = nil")
+	}
+}
+
+func TestDetectLineEnding(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"", LF},
+		{"a", LF},
+		{"a\nb\n", LF},
+		{"a\r\nb\r\n", CRLF},
+		{"a\rb\r", CR},
+		{"a\r\nb\r\nc\n", CRLF},
+		{"a\r\nb\nc\n", LF},
+	}
+	for _, tt := range tests {
+		if actual := DetectLineEnding(tt.text); actual != tt.expected {
+			t.Errorf("DetectLineEnding(%q) = %q, want %q", tt.text, actual, tt.expected)
+		}
+	}
+}
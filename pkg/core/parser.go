@@ -0,0 +1,73 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedFile is returned when no registered parser accepts a file
+var ErrUnsupportedFile = errors.New("unsupported file")
+
+// Parser turns the raw bytes of a file into a SourceFile
+type Parser interface {
+	Parse(path string, data []byte) (SourceFile, error)
+}
+
+// ParserFunc adapts an ordinary function to the Parser interface
+type ParserFunc func(path string, data []byte) (SourceFile, error)
+
+// Parse calls f(path, data)
+func (f ParserFunc) Parse(path string, data []byte) (SourceFile, error) {
+	return f(path, data)
+}
+
+// FileTypeRegistration associates a file type with its detection rule and parser
+type FileTypeRegistration struct {
+	Type   FileType
+	Detect func(path string) bool
+	Parser Parser
+}
+
+// ParserRegistry resolves file paths to file types and parses them.
+// Registrations are consulted in the order they were added, so more
+// specific rules (pom.xml) must be registered before general ones (*.xml).
+type ParserRegistry struct {
+	registrations []FileTypeRegistration
+}
+
+// NewParserRegistry creates an empty parser registry
+func NewParserRegistry() *ParserRegistry {
+	return &ParserRegistry{}
+}
+
+// Register adds a file type registration to the registry
+func (r *ParserRegistry) Register(registration FileTypeRegistration) {
+	r.registrations = append(r.registrations, registration)
+}
+
+// Detect returns the file type of the first registration whose rule matches the path
+func (r *ParserRegistry) Detect(path string) (FileType, bool) {
+	if registration, ok := r.lookup(path); ok {
+		return registration.Type, true
+	}
+	return PlainText, false
+}
+
+// Parse parses the file with the parser registered for its type
+func (r *ParserRegistry) Parse(path string, data []byte) (SourceFile, error) {
+	registration, ok := r.lookup(path)
+	if !ok || registration.Parser == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFile, path)
+	}
+	return registration.Parser.Parse(path, data)
+}
+
+// lookup finds the registration responsible for the path
+func (r *ParserRegistry) lookup(path string) (FileTypeRegistration, bool) {
+	for _, registration := range r.registrations {
+		if registration.Detect(path) {
+			return registration, true
+		}
+	}
+	return FileTypeRegistration{}, false
+}
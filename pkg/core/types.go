@@ -9,31 +9,154 @@ import (
 type Recipe interface {
 	GetDisplayName() string
 	GetDescription() string
+	GetSourceTypes() []FileType
 	Apply(ctx context.Context, sourceFile SourceFile) (SourceFile, error)
 }
 
+// ScanningRecipe is implemented by recipes that need to see every source file at
+// once, for example to generate new files or to merge one file into another.
+// ApplyAll returns the resulting set of files: files missing from the result are
+// deleted and files with paths that did not exist before are created.
+type ScanningRecipe interface {
+	Recipe
+	ApplyAll(ctx context.Context, sourceFiles []SourceFile) ([]SourceFile, error)
+}
+
 // SourceFile represents a source file that can be transformed
 type SourceFile interface {
 	GetPath() string
 	GetContent() string
 	SetContent(content string)
 	GetType() FileType
+	GetEncoding() Encoding
+	GetConfigKind() ConfigKind
 	Save(writer io.Writer) error
 }
 
-// FileType represents the type of configuration file
+// FileType represents the type of source file
 type FileType int
 
 const (
 	Properties FileType = iota
 	YAML
 	Java
+	Kotlin
+	XML
+	MavenPOM
+	GradleGroovy
+	GradleKotlin
+	Dockerfile
+	DotEnv
+	PlainText
+)
+
+// String returns a human readable name for the file type
+func (t FileType) String() string {
+	switch t {
+	case Properties:
+		return "properties"
+	case YAML:
+		return "yaml"
+	case Java:
+		return "java"
+	case Kotlin:
+		return "kotlin"
+	case XML:
+		return "xml"
+	case MavenPOM:
+		return "maven-pom"
+	case GradleGroovy:
+		return "gradle-groovy"
+	case GradleKotlin:
+		return "gradle-kotlin"
+	case Dockerfile:
+		return "dockerfile"
+	case DotEnv:
+		return "dotenv"
+	default:
+		return "text"
+	}
+}
+
+// ConfigKind classifies the role a configuration file plays in a project
+type ConfigKind int
+
+const (
+	OtherConfig ConfigKind = iota
+	SpringApplicationConfig
+	SpringProfileConfig
+	SpringCloudConfigRepo
+	I18nBundle
+	BuildConfig
+	// SpringTestConfig is a test resource named like Spring configuration, such as
+	// src/test/resources/application.test.properties, loaded through
+	// @TestPropertySource(locations = ...) or @PropertySource
+	SpringTestConfig
+	// DeploymentConfig sets Spring properties through environment variables, such as
+	// a Dockerfile, a .env file, a docker-compose file or a Kubernetes manifest
+	DeploymentConfig
 )
 
+// String returns a human readable name for the config kind
+func (k ConfigKind) String() string {
+	switch k {
+	case SpringApplicationConfig:
+		return "spring-config"
+	case SpringProfileConfig:
+		return "spring-profile-config"
+	case SpringCloudConfigRepo:
+		return "spring-cloud-config-repo"
+	case I18nBundle:
+		return "i18n-bundle"
+	case BuildConfig:
+		return "build-config"
+	case SpringTestConfig:
+		return "spring-test-config"
+	case DeploymentConfig:
+		return "deployment-config"
+	default:
+		return "other"
+	}
+}
+
+// IsSpringConfig reports whether files of this kind are read by Spring as application configuration
+func (k ConfigKind) IsSpringConfig() bool {
+	return k == SpringApplicationConfig || k == SpringProfileConfig || k == SpringCloudConfigRepo ||
+		k == SpringTestConfig
+}
+
 // ExecutionContext provides context and configuration for recipe execution
 type ExecutionContext struct {
 	Options map[string]interface{}
 	Logger  Logger
+	// Findings are the problems recipes reported, in the order they were found
+	Findings []Finding
+}
+
+// Severity tells how serious a finding is
+type Severity int
+
+const (
+	Warning Severity = iota
+	Error
+)
+
+// String returns a human readable name for the severity
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Finding is a problem a recipe reports at a line of a source file, such as an
+// unknown or deprecated property
+type Finding struct {
+	Path     string
+	Line     int // 1-based line number
+	Key      string
+	Severity Severity
+	Message  string
 }
 
 // Logger interface for logging recipe execution
@@ -64,11 +187,15 @@ const (
 	CommentOut
 )
 
-// SpringConfigFile represents a Spring configuration file
+// SpringConfigFile represents a Spring configuration file.
+// Content holds the decoded text with "\n" line endings; Encoding records
+// how it is stored on disk.
 type SpringConfigFile struct {
-	Path    string
-	Content string
-	Type    FileType
+	Path     string
+	Content  string
+	Type     FileType
+	Kind     ConfigKind
+	Encoding Encoding
 }
 
 // GetPath returns the file path
@@ -91,16 +218,42 @@ func (f *SpringConfigFile) GetType() FileType {
 	return f.Type
 }
 
-// Save writes the file content to the provided writer
+// GetConfigKind returns the role the file plays in the project
+func (f *SpringConfigFile) GetConfigKind() ConfigKind {
+	return f.Kind
+}
+
+// GetEncoding returns the charset, BOM and line ending of the file
+func (f *SpringConfigFile) GetEncoding() Encoding {
+	return f.Encoding
+}
+
+// Save writes the file content to the provided writer using the file's encoding
 func (f *SpringConfigFile) Save(writer io.Writer) error {
-	_, err := writer.Write([]byte(f.Content))
+	data, err := EncodeContent(f.Content, f.Encoding, f.Type)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
 	return err
 }
 
+// EmbeddedSourceFile is Spring configuration embedded in another file, such as
+// the application.yml block of a Kubernetes ConfigMap. Recipes edit it like any
+// other file and the runner writes the edits back into the host.
+type EmbeddedSourceFile struct {
+	SpringConfigFile
+	Host     SourceFile
+	Key      string // keys leading to the configuration in the host, joined by "/"
+	Document int    // index of the YAML document of the host holding the key
+	Line     int    // index of the host line holding the first line of the content
+}
+
 // BaseRecipe provides common functionality for all recipes
 type BaseRecipe struct {
 	DisplayName string
 	Description string
+	SourceTypes []FileType
 }
 
 // GetDisplayName returns the recipe display name
@@ -112,3 +265,22 @@ func (r *BaseRecipe) GetDisplayName() string {
 func (r *BaseRecipe) GetDescription() string {
 	return r.Description
 }
+
+// GetSourceTypes returns the file types the recipe visits; empty means all types
+func (r *BaseRecipe) GetSourceTypes() []FileType {
+	return r.SourceTypes
+}
+
+// Visits reports whether the recipe should be applied to files of the given type
+func Visits(recipe Recipe, fileType FileType) bool {
+	sourceTypes := recipe.GetSourceTypes()
+	if len(sourceTypes) == 0 {
+		return true
+	}
+	for _, sourceType := range sourceTypes {
+		if sourceType == fileType {
+			return true
+		}
+	}
+	return false
+}
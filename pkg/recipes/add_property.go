@@ -2,19 +2,25 @@ package recipes
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/openrewrite/rewrite-spring-go/pkg/core"
 	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
 )
 
-// AddSpringPropertyRecipe adds properties to Spring configuration files
+// AddSpringPropertyRecipe adds properties to Spring configuration files. A value
+// written as a YAML flow sequence or mapping, such as "[a, b]" or "{x: 1}",
+// adds a list or map property.
 type AddSpringPropertyRecipe struct {
 	core.BaseRecipe
 	Property        string
 	Value           string
 	Comment         string
 	PathExpressions []string
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
 }
 
 // NewAddSpringPropertyRecipe creates a new AddSpringProperty recipe
@@ -33,6 +39,7 @@ func NewAddSpringPropertyRecipe(property, value, comment string, pathExpressions
 		BaseRecipe: core.BaseRecipe{
 			DisplayName: "Add a spring configuration property",
 			Description: "Add a spring configuration property to a configuration file if it does not already exist in that file.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
 		},
 		Property:        property,
 		Value:           value,
@@ -47,11 +54,20 @@ func (r *AddSpringPropertyRecipe) Apply(ctx context.Context, sourceFile core.Sou
 		return sourceFile, nil
 	}
 
+	if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+
+	properties, err := r.valueProperties()
+	if err != nil {
+		return sourceFile, fmt.Errorf("invalid value for %s: %w", r.Property, err)
+	}
+
 	switch sourceFile.GetType() {
 	case core.Properties:
-		return r.addToProperties(sourceFile)
+		return r.addToProperties(ctx, sourceFile, properties)
 	case core.YAML:
-		return r.addToYAML(sourceFile)
+		return r.addToYAML(ctx, sourceFile, properties)
 	default:
 		return sourceFile, nil
 	}
@@ -67,152 +83,263 @@ func (r *AddSpringPropertyRecipe) shouldProcessFile(filePath string) bool {
 	return false
 }
 
-// addToProperties adds the property to a properties file
-func (r *AddSpringPropertyRecipe) addToProperties(sourceFile core.SourceFile) (core.SourceFile, error) {
-	content := sourceFile.GetContent()
-
-	// Check if property already exists
-	if r.propertyExistsInProperties(content) {
-		return sourceFile, nil
+// valueProperties returns the properties the value defines: one for a scalar,
+// or one per element of a flow sequence or mapping
+func (r *AddSpringPropertyRecipe) valueProperties() ([]utils.YAMLProperty, error) {
+	value := strings.TrimSpace(r.Value)
+	if !strings.HasPrefix(value, "[") && !strings.HasPrefix(value, "{") {
+		return []utils.YAMLProperty{{Key: r.Property, Value: r.Value}}, nil
 	}
 
-	var newContent strings.Builder
-	newContent.WriteString(content)
+	properties, err := utils.ParseYAML("value: " + value).FlattenDocument(0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range properties {
+		properties[i].Key = r.Property + strings.TrimPrefix(properties[i].Key, "value")
+	}
+	return properties, nil
+}
 
-	// Add newline if content doesn't end with one
-	if !strings.HasSuffix(content, "\n") {
-		newContent.WriteString("\n")
+// addToProperties adds the missing properties to the end of the first document
+// of a properties file
+func (r *AddSpringPropertyRecipe) addToProperties(ctx context.Context, sourceFile core.SourceFile, properties []utils.YAMLProperty) (core.SourceFile, error) {
+	logger := core.GetExecutionContext(ctx).Logger
+	doc := utils.ParseProperties(sourceFile.GetContent())
+
+	end := len(doc.Lines)
+	for i, line := range doc.Lines {
+		if utils.IsPropertiesDocumentSeparator(line) {
+			end = i
+			break
+		}
+	}
+	var defined []string
+	for _, entry := range doc.Entries {
+		if entry.Line < end {
+			defined = append(defined, entry.Key)
+		}
+	}
+	properties = r.missingProperties(defined, properties)
+	if len(properties) == 0 {
+		return sourceFile, nil
+	}
+	if err := r.checkListIndexes(defined); err != nil {
+		logger.Warn("Cannot add %s to %s: %v", r.Property, sourceFile.GetPath(), err)
+		return sourceFile, nil
 	}
 
-	// Add comment if provided
+	var lines []string
 	if r.Comment != "" {
-		newContent.WriteString("# ")
-		newContent.WriteString(r.Comment)
-		newContent.WriteString("\n")
+		lines = append(lines, "# "+r.Comment)
+	}
+	for _, property := range properties {
+		lines = append(lines, utils.EscapePropertiesKey(property.Key)+"="+utils.EscapePropertiesValue(property.Value))
 	}
 
-	// Add the property
-	newContent.WriteString(r.Property)
-	newContent.WriteString("=")
-	newContent.WriteString(r.Value)
-	newContent.WriteString("\n")
+	// Insert after the last non-blank line, keeping a trailing newline
+	for end > 0 && strings.TrimSpace(doc.Lines[end-1]) == "" {
+		end--
+	}
+	if end == len(doc.Lines) {
+		lines = append(lines, "")
+	}
+	doc.InsertLines(end, lines...)
 
-	sourceFile.SetContent(newContent.String())
+	sourceFile.SetContent(doc.String())
 	return sourceFile, nil
 }
 
-// addToYAML adds the property to a YAML file
-func (r *AddSpringPropertyRecipe) addToYAML(sourceFile core.SourceFile) (core.SourceFile, error) {
-	content := sourceFile.GetContent()
+// addToYAML adds the property to the document of a YAML file that applies to
+// every profile. The remaining path is nested under the deepest mapping that
+// already exists, using the indentation of the file.
+func (r *AddSpringPropertyRecipe) addToYAML(ctx context.Context, sourceFile core.SourceFile, properties []utils.YAMLProperty) (core.SourceFile, error) {
+	logger := core.GetExecutionContext(ctx).Logger
+	doc := utils.ParseYAML(sourceFile.GetContent())
 
-	// Check if property already exists
-	if r.propertyExistsInYAML(content) {
-		return sourceFile, nil
+	document := findYAMLDocument(doc, "")
+	if document < 0 {
+		document = 0
 	}
 
-	// Convert property to YAML format
-	yamlProperty := r.convertToYAML()
+	// Flattening also finds properties defined by sequences and flow collections
+	defined, err := doc.FlattenDocument(document)
+	if err != nil {
+		logger.Warn("Cannot add %s to %s: %v", r.Property, sourceFile.GetPath(), err)
+		return sourceFile, nil
+	}
+	definedKeys := make([]string, len(defined))
+	for i, property := range defined {
+		definedKeys[i] = property.Key
+	}
+	properties = r.missingProperties(definedKeys, properties)
+	if len(properties) == 0 {
+		return sourceFile, nil
+	}
+	if err := r.checkListIndexes(definedKeys); err != nil {
+		logger.Warn("Cannot add %s to %s: %v", r.Property, sourceFile.GetPath(), err)
+		return sourceFile, nil
+	}
 
-	var newContent strings.Builder
-	newContent.WriteString(content)
+	if entry := r.flowSequence(doc, document, properties); entry != nil {
+		items, _ := entry.FlowSequenceItems()
+		raws := make([]string, len(items), len(items)+1)
+		for i, item := range items {
+			raws[i] = item.Raw
+		}
+		raws = append(raws, utils.FormatYAMLFlowScalar(properties[0].Value, ""))
+		doc.SetValue(entry, utils.FormatYAMLFlowSequence(raws, items, entry.RawValue))
+		sourceFile.SetContent(doc.String())
+		return sourceFile, nil
+	}
 
-	// Add newline if content doesn't end with one
-	if !strings.HasSuffix(content, "\n") {
-		newContent.WriteString("\n")
+	// The addition is converted on its own, where the lists of the property
+	// start at index 0; its paths are mapped back to the indexes of the property
+	var content strings.Builder
+	for _, property := range properties {
+		key := zeroListIndexes(r.Property) + strings.TrimPrefix(property.Key, r.Property)
+		content.WriteString(utils.EscapePropertiesKey(key) + "=" + utils.EscapePropertiesValue(property.Value) + "\n")
+	}
+	unit := doc.IndentUnit()
+	converted, err := utils.ConvertPropertiesToYAMLWithIndent(content.String(), unit)
+	if err != nil {
+		return sourceFile, fmt.Errorf("invalid value for %s: %w", r.Property, err)
+	}
+	addition := utils.ParseYAML(strings.TrimSuffix(converted, "\n"))
+
+	parent, added := findYAMLAncestor(doc, document, addition, r.Property)
+	if parent == nil {
+		// No part of the path exists yet, so the whole addition goes to the end of the document
+		lines := addition.Lines
+		if r.Comment != "" {
+			lines = append([]string{"# " + r.Comment}, lines...)
+		}
+		mergeYAMLInto(doc, document, strings.Join(lines, "\n"))
+		sourceFile.SetContent(doc.String())
+		return sourceFile, nil
+	}
+	// Items are appended to a sequence, keys to a mapping
+	appendsItems := parent.IsSequence() || (isYAMLMapping(parent) && len(parent.Children) == 0)
+	if (added.IsSequence() && !appendsItems) || (!added.IsSequence() && !isYAMLMapping(parent)) {
+		logger.Warn("Cannot add %s to %s: %s already holds a value", r.Property, sourceFile.GetPath(), parent.Path)
+		return sourceFile, nil
 	}
 
-	newContent.WriteString(yamlProperty)
+	indent := parent.Indent + unit
+	if len(parent.Children) > 0 {
+		indent = parent.Children[0].Indent
+	}
+	addedIndent := added.Indent + unit
+	if len(added.Children) > 0 {
+		addedIndent = added.Children[0].Indent
+	}
+	lines := reindentLines(yamlChildLines(addition, added), indent-addedIndent)
+	if r.Comment != "" {
+		lines = append([]string{strings.Repeat(" ", indent) + "# " + r.Comment}, lines...)
+	}
+	doc.InsertLines(parent.End, lines...)
 
-	sourceFile.SetContent(newContent.String())
+	sourceFile.SetContent(doc.String())
 	return sourceFile, nil
 }
 
-// propertyExistsInProperties checks if the property already exists in properties file
-func (r *AddSpringPropertyRecipe) propertyExistsInProperties(content string) bool {
-	lines := strings.Split(content, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "#") || line == "" {
+// flowSequence returns the flow sequence of scalars the property appends an
+// item to, or nil when the property is not a scalar item of one
+func (r *AddSpringPropertyRecipe) flowSequence(doc *utils.YAMLDocument, document int, properties []utils.YAMLProperty) *utils.YAMLEntry {
+	indexes := findKeyIndexes(r.Property)
+	if len(properties) != 1 || properties[0].Key != r.Property || len(indexes) == 0 ||
+		indexes[len(indexes)-1].end != len(r.Property) {
+		return nil
+	}
+	index := indexes[len(indexes)-1]
+	for _, entry := range doc.Entries {
+		if entry.Document != document || !utils.EqualKeys(entry.Path, r.Property[:index.start]) {
 			continue
 		}
-
-		if strings.HasPrefix(line, r.Property+"=") {
-			return true
+		if items, ok := entry.FlowSequenceItems(); ok && len(items) == index.value {
+			return entry
 		}
+		return nil
 	}
-	return false
+	return nil
 }
 
-// propertyExistsInYAML checks if the property already exists in YAML file
-func (r *AddSpringPropertyRecipe) propertyExistsInYAML(content string) bool {
-	// Simple check - in a full implementation, you'd use a proper YAML parser
-	propertyParts := strings.Split(r.Property, ".")
-
-	// Check for the leaf property key
-	leafKey := propertyParts[len(propertyParts)-1]
-	lines := strings.Split(content, "\n")
+// missingProperties returns the properties of the value that are not defined
+// yet, comparing keys with relaxed binding. A defined key below a property
+// counts as a definition, and a list is only added when none of it is defined.
+func (r *AddSpringPropertyRecipe) missingProperties(defined []string, properties []utils.YAMLProperty) []utils.YAMLProperty {
+	var missing []utils.YAMLProperty
+	for _, property := range properties {
+		key := property.Key
+		if index := strings.Index(key[len(r.Property):], "["); index >= 0 {
+			key = key[:len(r.Property)+index]
+		}
 
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, leafKey+":") {
-			return true
+		isDefined := false
+		for _, definedKey := range defined {
+			if utils.IsKeyOrSubkey(definedKey, key) {
+				isDefined = true
+				break
+			}
+		}
+		if !isDefined {
+			missing = append(missing, property)
 		}
 	}
-	return false
+	return missing
 }
 
-// convertToYAML converts a dot-notation property to YAML format
-func (r *AddSpringPropertyRecipe) convertToYAML() string {
-	propertyParts := strings.Split(r.Property, ".")
-
-	var yamlBuilder strings.Builder
-	indent := ""
-
-	// Add comment if provided
-	if r.Comment != "" {
-		yamlBuilder.WriteString("# ")
-		yamlBuilder.WriteString(r.Comment)
-		yamlBuilder.WriteString("\n")
-	}
-
-	for i, part := range propertyParts {
-		yamlBuilder.WriteString(indent)
-		yamlBuilder.WriteString(part)
-		yamlBuilder.WriteString(":")
-
-		if i == len(propertyParts)-1 {
-			// Last part - add the value
-			yamlBuilder.WriteString(" ")
-			if r.needsQuotes(r.Value) {
-				yamlBuilder.WriteString("\"")
-				yamlBuilder.WriteString(r.Value)
-				yamlBuilder.WriteString("\"")
-			} else {
-				yamlBuilder.WriteString(r.Value)
+// findYAMLAncestor returns the deepest entry of the target document at the path
+// of key or one of its ancestors, together with the entry at the same path in
+// the addition, or nils when no part of the path exists yet. The lists of key
+// start at index 0 in the addition.
+func findYAMLAncestor(target *utils.YAMLDocument, document int, addition *utils.YAMLDocument, key string) (*utils.YAMLEntry, *utils.YAMLEntry) {
+	for i := len(addition.Entries) - 1; i >= 0; i-- {
+		added := addition.Entries[i]
+		if !utils.IsKeyOrSubkey(zeroListIndexes(key), added.Path) {
+			continue
+		}
+		path := copyListIndexes(added.Path, key)
+		for _, existing := range target.Entries {
+			if existing.Document == document && utils.EqualKeys(existing.Path, path) {
+				return existing, added
 			}
 		}
-		yamlBuilder.WriteString("\n")
-		indent += "  "
 	}
-
-	return yamlBuilder.String()
+	return nil, nil
 }
 
-// needsQuotes determines if a YAML value needs to be quoted
-func (r *AddSpringPropertyRecipe) needsQuotes(value string) bool {
-	// Simple heuristic - quote if contains special characters
-	specialChars := []string{":", "[", "]", "{", "}", ",", "&", "*", "#", "?", "|", "-", "<", ">", "=", "!", "%", "@", "`"}
-
-	for _, char := range specialChars {
-		if strings.Contains(value, char) {
-			return true
-		}
+// yamlChildLines returns the lines holding the children of an entry. The dash
+// of an item is blanked, so that a child on its line lines up with the others.
+func yamlChildLines(doc *utils.YAMLDocument, entry *utils.YAMLEntry) []string {
+	if !entry.Item {
+		return doc.Lines[entry.Line+1 : entry.End]
 	}
-
-	// Quote if it looks like a number but should be treated as string
-	if strings.Contains(value, ".") && len(strings.Split(value, ".")) > 2 {
-		return true
+	lines := append([]string{}, doc.Lines[entry.Line:entry.End]...)
+	lines[0] = lines[0][:entry.Indent] + " " + lines[0][entry.Indent+1:]
+	if strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
 	}
+	return lines
+}
 
-	return false
+// checkListIndexes checks that every list index of the property either exists
+// or appends an item to its list, since Spring stops binding a list at the
+// first missing index
+func (r *AddSpringPropertyRecipe) checkListIndexes(defined []string) error {
+	lists := listIndexes(defined)
+	for _, index := range findKeyIndexes(r.Property) {
+		list := r.Property[:index.start]
+		var items map[int]bool
+		for definedList, indexes := range lists {
+			if utils.EqualKeys(definedList, list) {
+				items = indexes
+				break
+			}
+		}
+		if !items[index.value] && index.value != len(items) {
+			return fmt.Errorf("%s has %d items, so index %d would leave a gap", list, len(items), index.value)
+		}
+	}
+	return nil
 }
@@ -2,20 +2,37 @@ package recipes
 
 import (
 	"context"
-	"regexp"
+	"fmt"
 	"strings"
 
 	"github.com/openrewrite/rewrite-spring-go/pkg/core"
 	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
 )
 
+// propertyLookupMethods are the methods of Environment and PropertyResolver
+// whose first argument is a property key
+var propertyLookupMethods = []string{"getProperty", "getRequiredProperty", "containsProperty"}
+
 // ChangeSpringPropertyKeyRecipe changes Spring property keys in configuration files
 type ChangeSpringPropertyKeyRecipe struct {
 	core.BaseRecipe
-	OldPropertyKey  string
-	NewPropertyKey  string
-	Except          []string
+	// OldPropertyKey is a key glob such as "management.metrics.binders.*.enabled";
+	// parenthesised parts like "(*)" are captured for use in NewPropertyKey as $1, $2, ...
+	OldPropertyKey string
+	NewPropertyKey string
+	// Except lists patterns of subkeys below OldPropertyKey that keep their key
+	Except []string
+	// Regex treats OldPropertyKey and Except as regular expressions instead of key globs
+	Regex bool
+	// PathExpressions optionally restricts the recipe to matching files; by default
+	// every Spring configuration file is processed
 	PathExpressions []string
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
+
+	oldKeyPattern  *utils.KeyPattern
+	exceptPatterns []*utils.KeyPattern
 }
 
 // NewChangeSpringPropertyKeyRecipe creates a new ChangeSpringPropertyKey recipe
@@ -23,16 +40,17 @@ func NewChangeSpringPropertyKeyRecipe(oldKey, newKey string, except []string) *C
 	return &ChangeSpringPropertyKeyRecipe{
 		BaseRecipe: core.BaseRecipe{
 			DisplayName: "Change the key of a Spring application property",
-			Description: "Change Spring application property keys existing in either Properties or YAML files, and in @Value annotations.",
+			Description: "Change Spring application property keys existing in either Properties or YAML files, " +
+				"and the references to them in `${...}` placeholders and SpEL expressions of configuration values " +
+				"and Java or Kotlin string literals, in @ConditionalOnProperty and @ConfigurationProperties, in " +
+				"Environment lookups such as getProperty, and in test properties of @SpringBootTest, " +
+				"@TestPropertySource and DynamicPropertyRegistry, and in environment variables such as " +
+				"SPRING_DATASOURCE_URL set by Dockerfiles, .env files, docker-compose files and Kubernetes manifests.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML, core.Java, core.Kotlin, core.Dockerfile, core.DotEnv},
 		},
 		OldPropertyKey: oldKey,
 		NewPropertyKey: newKey,
 		Except:         except,
-		PathExpressions: []string{
-			"**/application.properties",
-			"**/application.yml",
-			"**/application.yaml",
-		},
 	}
 }
 
@@ -42,13 +60,26 @@ func (r *ChangeSpringPropertyKeyRecipe) Apply(ctx context.Context, sourceFile co
 		return sourceFile, nil
 	}
 
+	if err := r.compilePatterns(); err != nil {
+		return sourceFile, err
+	}
+
+	if sourceFile.GetConfigKind() == core.DeploymentConfig {
+		renameEnvironmentVariables(sourceFile, r.renameReference)
+		return sourceFile, nil
+	}
+
+	if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+
 	switch sourceFile.GetType() {
 	case core.Properties:
 		return r.applyToProperties(sourceFile)
 	case core.YAML:
-		return r.applyToYAML(sourceFile)
-	case core.Java:
-		return r.applyToJava(sourceFile)
+		return r.applyToYAML(ctx, sourceFile)
+	case core.Java, core.Kotlin:
+		return r.applyToSource(sourceFile)
 	default:
 		return sourceFile, nil
 	}
@@ -70,114 +101,419 @@ func (r *ChangeSpringPropertyKeyRecipe) shouldProcessFile(filePath string) bool
 
 // applyToProperties applies the transformation to properties files
 func (r *ChangeSpringPropertyKeyRecipe) applyToProperties(sourceFile core.SourceFile) (core.SourceFile, error) {
-	content := sourceFile.GetContent()
-	lines := strings.Split(content, "\n")
+	doc := utils.ParseProperties(sourceFile.GetContent())
 	modified := false
 
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "#") || line == "" {
+	for i := 0; i < len(doc.Entries); i++ {
+		entry := doc.Entries[i]
+		if !r.shouldTransformKey(entry.Key) {
 			continue
 		}
-
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
+		if newKey := r.transformPropertyKey(entry.Key); newKey != entry.Key {
+			doc.SetKey(entry, newKey)
+			modified = true
 		}
+	}
 
-		key := strings.TrimSpace(parts[0])
-
-		if r.shouldTransformKey(key) {
-			newKey := r.transformPropertyKey(key)
-			if newKey != key {
-				lines[i] = strings.Replace(lines[i], key+"=", newKey+"=", 1)
+	// Values referring to renamed keys follow them
+	for _, entry := range doc.Entries {
+		for i := entry.Line; i < entry.End; i++ {
+			start := 0
+			if i == entry.Line {
+				start = entry.ValueStart
+			}
+			if renamed, ok := utils.RenamePropertyReferences(doc.Lines[i][start:], r.renameReference); ok {
+				doc.Lines[i] = doc.Lines[i][:start] + renamed
 				modified = true
 			}
 		}
 	}
 
 	if modified {
-		sourceFile.SetContent(strings.Join(lines, "\n"))
+		sourceFile.SetContent(doc.String())
 	}
 
 	return sourceFile, nil
 }
 
-// applyToYAML applies the transformation to YAML files
-func (r *ChangeSpringPropertyKeyRecipe) applyToYAML(sourceFile core.SourceFile) (core.SourceFile, error) {
-	content := sourceFile.GetContent()
-
-	// Simple YAML key replacement - in a full implementation, you'd use a proper YAML parser
-	lines := strings.Split(content, "\n")
+// applyToYAML moves every matching YAML node, with its subtree and comments, to
+// the path of its new key
+func (r *ChangeSpringPropertyKeyRecipe) applyToYAML(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	logger := core.GetExecutionContext(ctx).Logger
+	doc := utils.ParseYAML(sourceFile.GetContent())
 	modified := false
 
-	for i, line := range lines {
-		if strings.Contains(line, ":") && !strings.HasPrefix(strings.TrimSpace(line), "#") {
-			// Extract the key part before the colon
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				keyPart := strings.TrimSpace(parts[0])
-
-				if r.shouldTransformKey(keyPart) {
-					newKey := r.transformPropertyKey(keyPart)
-					if newKey != keyPart {
-						lines[i] = strings.Replace(line, keyPart+":", newKey+":", 1)
-						modified = true
-					}
-				}
-			}
+	// Paths that received a moved node, per document, so they are not moved again
+	moved := make(map[int]map[string]bool)
+	for {
+		entry := r.findYAMLEntryToMove(doc, moved)
+		if entry == nil {
+			break
+		}
+
+		oldPath, newPath := entry.Path, r.transformPropertyKey(entry.Path)
+		if moved[entry.Document] == nil {
+			moved[entry.Document] = make(map[string]bool)
+		}
+		moved[entry.Document][newPath] = true
+
+		for _, conflict := range moveYAMLEntry(doc, entry, newPath) {
+			logger.Warn("%s already sets %s to %q; dropping %q from %s",
+				sourceFile.GetPath(), conflict.Path, conflict.ExistingValue, conflict.IgnoredValue, oldPath)
+		}
+		modified = true
+	}
+
+	// Values referring to renamed keys follow them; keys never hold placeholders
+	for i, line := range doc.Lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if renamed, ok := utils.RenamePropertyReferences(line, r.renameReference); ok {
+			doc.Lines[i] = renamed
+			modified = true
 		}
 	}
 
 	if modified {
-		sourceFile.SetContent(strings.Join(lines, "\n"))
+		sourceFile.SetContent(keepTrailingNewline(doc.String(), sourceFile.GetContent()))
 	}
 
 	return sourceFile, nil
 }
 
-// applyToJava applies the transformation to Java files (for @Value annotations)
-func (r *ChangeSpringPropertyKeyRecipe) applyToJava(sourceFile core.SourceFile) (core.SourceFile, error) {
+// findYAMLEntryToMove returns the first entry whose whole subtree moves to a new
+// key. Sequence items are never moved on their own; their list moves with its key.
+func (r *ChangeSpringPropertyKeyRecipe) findYAMLEntryToMove(doc *utils.YAMLDocument, moved map[int]map[string]bool) *utils.YAMLEntry {
+	for _, entry := range doc.Entries {
+		if entry.Item || isMovedYAMLEntry(entry, moved[entry.Document]) || !r.shouldMoveYAMLSubtree(entry) {
+			continue
+		}
+		if r.transformPropertyKey(entry.Path) != entry.Path {
+			return entry
+		}
+	}
+	return nil
+}
+
+// shouldMoveYAMLSubtree checks that an entry and all entries beneath it match,
+// so that exceptions below the old key stay where they are
+func (r *ChangeSpringPropertyKeyRecipe) shouldMoveYAMLSubtree(entry *utils.YAMLEntry) bool {
+	if !r.shouldTransformKey(entry.Path) {
+		return false
+	}
+	for _, child := range entry.Children {
+		if !r.shouldMoveYAMLSubtree(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// isMovedYAMLEntry reports whether an entry lies at or beneath a path that
+// received a moved node, or holds one, as "a.b" does after moving to "a.b.c".
+// The ancestor must be skipped too: it still matches the old key, and moving
+// it again would move the moved node along with it, without end.
+func isMovedYAMLEntry(entry *utils.YAMLEntry, moved map[string]bool) bool {
+	for path := range moved {
+		if utils.IsKeyOrSubkey(entry.Path, path) || utils.IsKeyOrSubkey(path, entry.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// moveYAMLEntry moves an entry to newPath. A key that stays under the same parent
+// is renamed in place; otherwise the entry is merged into the mappings of the
+// new path, which are created as needed, and removed, pruning emptied ancestors.
+// A new path beneath an existing sequence item is merged into that item.
+func moveYAMLEntry(doc *utils.YAMLDocument, entry *utils.YAMLEntry, newPath string) []mergeConflict {
+	document := entry.Document
+	if relative, ok := relativeYAMLPath(entry, newPath); ok && doc.FindInDocument(newPath, document) == nil &&
+		(strings.Contains(entry.Key, ".") || len(utils.SplitYAMLPath(relative)) == 1) {
+		doc.SetKey(entry, relative)
+		return nil
+	}
+
+	unit := doc.IndentUnit()
+	keys := utils.SplitYAMLPath(newPath)
+	if item := findYAMLItemAncestor(doc, document, newPath); item != nil {
+		oldPath := entry.Path
+		lines := movedYAMLLines(doc, entry, keys[len(utils.SplitYAMLPath(item.Path)):], unit)
+		conflicts := mergeYAMLIntoItem(doc, item, strings.Join(lines, "\n"))
+		removeYAMLEntry(doc, doc.FindInDocument(oldPath, document))
+		return conflicts
+	}
+
+	lines := movedYAMLLines(doc, entry, keys, unit)
+	oldPath := entry.Path
+	if utils.IsKeyOrSubkey(newPath, oldPath) || utils.IsKeyOrSubkey(oldPath, newPath) {
+		// The entry would conflict with its own new path
+		removeYAMLEntry(doc, entry)
+		return mergeYAMLInto(doc, document, strings.Join(lines, "\n"))
+	}
+	// Merging first keeps the ancestors the paths share where they are, and
+	// only the branches the removal leaves empty are pruned
+	conflicts := mergeYAMLInto(doc, document, strings.Join(lines, "\n"))
+	removeYAMLEntry(doc, doc.FindInDocument(oldPath, document))
+	return conflicts
+}
+
+// movedYAMLLines returns the lines of an entry moved to the nested keys, with
+// its comments, value and subtree, starting at column 0
+func movedYAMLLines(doc *utils.YAMLDocument, entry *utils.YAMLEntry, keys []string, unit int) []string {
+	leafIndent := (len(keys) - 1) * unit
+
+	var lines []string
+	for i, key := range keys[:len(keys)-1] {
+		lines = append(lines, strings.Repeat(" ", i*unit)+utils.FormatYAMLKey(key)+":")
+	}
+	commentStart := doc.LeadingCommentStart(entry)
+	if entry.SharesItemLine() {
+		// Comments above the dash belong to the item
+		commentStart = entry.Line
+	}
+	for _, comment := range doc.Lines[commentStart:entry.Line] {
+		lines = append(lines, strings.Repeat(" ", leafIndent)+strings.TrimSpace(comment))
+	}
+	// The leaf keeps the value, trailing comment and subtree of the moved entry
+	line := doc.Lines[entry.Line]
+	lines = append(lines, strings.Repeat(" ", leafIndent)+utils.FormatYAMLKey(keys[len(keys)-1])+line[entry.KeyEnd:])
+	return append(lines, reindentLines(doc.Lines[entry.Line+1:entry.End], leafIndent-entry.Indent)...)
+}
+
+// findYAMLItemAncestor returns the deepest sequence item holding a mapping
+// that path lies beneath, or nil
+func findYAMLItemAncestor(doc *utils.YAMLDocument, document int, path string) *utils.YAMLEntry {
+	var ancestor *utils.YAMLEntry
+	for _, entry := range doc.Entries {
+		if entry.Document == document && entry.Item && entry.RawValue == "" && len(entry.Children) > 0 &&
+			!entry.IsSequence() && utils.IsKeyOrSubkey(path, entry.Path) && !utils.EqualKeys(path, entry.Path) {
+			ancestor = entry
+		}
+	}
+	return ancestor
+}
+
+// mergeYAMLIntoItem merges YAML content into the mapping of a sequence item,
+// as mergeYAMLInto does for a document
+func mergeYAMLIntoItem(doc *utils.YAMLDocument, item *utils.YAMLEntry, content string) []mergeConflict {
+	indent := item.Children[0].Indent
+	start := item.Line
+	if !item.Children[0].SharesItemLine() {
+		start++
+	}
+	end := item.End
+	block := append([]string{}, doc.Lines[start:end]...)
+	if start == item.Line {
+		block[0] = block[0][:item.Indent] + " " + block[0][item.Indent+1:]
+	}
+
+	mapping := utils.ParseYAML(strings.Join(reindentLines(block, -indent), "\n"))
+	conflicts := mergeYAMLInto(mapping, 0, content)
+
+	merged := reindentLines(strings.Split(strings.TrimRight(mapping.String(), "\n"), "\n"), indent)
+	if start == item.Line {
+		merged[0] = merged[0][:item.Indent] + "-" + merged[0][item.Indent+1:]
+	}
+	lines := append([]string{}, doc.Lines[:start]...)
+	lines = append(lines, merged...)
+	doc.SetLines(append(lines, doc.Lines[end:]...))
+	return conflicts
+}
+
+// relativeYAMLPath returns newPath relative to the parent of entry, if it lies beneath it
+func relativeYAMLPath(entry *utils.YAMLEntry, newPath string) (string, bool) {
+	if entry.Parent == nil {
+		return newPath, true
+	}
+	rest := strings.TrimPrefix(newPath, entry.Parent.Path)
+	switch {
+	case len(rest) == len(newPath) || rest == "":
+		return "", false
+	case rest[0] == '.':
+		return rest[1:], true
+	case rest[0] == '[':
+		return rest, true
+	}
+	return "", false
+}
+
+// applyToSource renames the keys Java or Kotlin source refers to: bare keys in
+// @ConditionalOnProperty, @ConfigurationProperties and Environment lookups,
+// properties defined by tests, and property references in the placeholders of
+// any string literal
+func (r *ChangeSpringPropertyKeyRecipe) applyToSource(sourceFile core.SourceFile) (core.SourceFile, error) {
 	content := sourceFile.GetContent()
+	fileType := sourceFile.GetType()
 
-	// Pattern to match @Value("${property.key:defaultValue}")
-	valuePattern := regexp.MustCompile(`@Value\("?\$\{([^}:]+)([^}]*)\}"?\)`)
+	var edits []textEdit
+	for _, annotation := range utils.FindAnnotations(content, fileType, "ConditionalOnProperty") {
+		edits = append(edits, r.conditionalOnPropertyEdits(content, annotation)...)
+	}
+	for _, annotation := range utils.FindAnnotations(content, fileType, "ConfigurationProperties") {
+		if prefix := annotation.Attribute("prefix", "value"); prefix != nil && len(prefix.Literals) == 1 {
+			edits = append(edits, r.literalKeyEdits(content, prefix.Literals)...)
+		}
+	}
+	for _, call := range utils.FindMethodCalls(content, fileType, "", propertyLookupMethods...) {
+		edits = append(edits, r.literalKeyEdits(content, []utils.StringLiteral{call.Argument})...)
+	}
+	for _, property := range findTestProperties(content, fileType) {
+		if newKey, ok := r.renameReference(property.key); ok {
+			edits = append(edits, textEdit{start: property.keyStart, end: property.keyEnd, text: newKey})
+		}
+	}
+	content = applyTextEdits(content, edits)
 
-	modified := false
-	newContent := valuePattern.ReplaceAllStringFunc(content, func(match string) string {
-		submatch := valuePattern.FindStringSubmatch(match)
-		if len(submatch) >= 2 {
-			propertyKey := submatch[1]
-
-			if r.shouldTransformKey(propertyKey) {
-				newKey := r.transformPropertyKey(propertyKey)
-				if newKey != propertyKey {
-					modified = true
-					return strings.Replace(match, propertyKey, newKey, 1)
-				}
-			}
+	edits = nil
+	for _, literal := range utils.FindStringLiterals(content, fileType) {
+		if renamed, ok := utils.RenameLiteralPropertyReferences(content, literal, fileType, r.renameReference); ok {
+			edits = append(edits, textEdit{start: literal.Start, end: literal.End, text: renamed})
 		}
-		return match
-	})
+	}
+	content = applyTextEdits(content, edits)
 
-	if modified {
-		sourceFile.SetContent(newContent)
+	if content != sourceFile.GetContent() {
+		sourceFile.SetContent(content)
 	}
 
 	return sourceFile, nil
 }
 
-// shouldTransformKey checks if a key should be transformed
+// literalKeyEdits renames the keys held by string literals
+func (r *ChangeSpringPropertyKeyRecipe) literalKeyEdits(content string, literals []utils.StringLiteral) []textEdit {
+	var edits []textEdit
+	for _, literal := range literals {
+		if newKey, ok := r.renameReference(content[literal.Start:literal.End]); ok {
+			edits = append(edits, textEdit{start: literal.Start, end: literal.End, text: newKey})
+		}
+	}
+	return edits
+}
+
+// conditionalOnPropertyEdits renames the keys of a @ConditionalOnProperty, each
+// being the prefix joined with one of the names. When a renamed key leaves the
+// prefix, the prefix becomes the longest one shared by all keys.
+func (r *ChangeSpringPropertyKeyRecipe) conditionalOnPropertyEdits(content string, annotation utils.Annotation) []textEdit {
+	names := annotation.Attribute("name", "value")
+	if names == nil || len(names.Literals) == 0 {
+		return nil
+	}
+	prefixAttribute := annotation.Attribute("prefix")
+	if prefixAttribute == nil {
+		return r.literalKeyEdits(content, names.Literals)
+	}
+	if len(prefixAttribute.Literals) != 1 {
+		return nil
+	}
+	prefixLiteral := prefixAttribute.Literals[0]
+	prefix := content[prefixLiteral.Start:prefixLiteral.End]
+	trimmedPrefix := strings.TrimSuffix(prefix, ".")
+
+	keys := make([]string, len(names.Literals))
+	renamed := false
+	for i, literal := range names.Literals {
+		key := content[literal.Start:literal.End]
+		if trimmedPrefix != "" {
+			key = trimmedPrefix + "." + key
+		}
+		if newKey, ok := r.renameReference(key); ok {
+			key = newKey
+			renamed = true
+		}
+		keys[i] = key
+	}
+	if !renamed {
+		return nil
+	}
+
+	newPrefix := trimmedPrefix
+	for _, key := range keys {
+		if newPrefix != "" && !strings.HasPrefix(key, newPrefix+".") {
+			newPrefix = commonKeyPrefix(keys)
+			break
+		}
+	}
+
+	var edits []textEdit
+	if newPrefix != trimmedPrefix {
+		text := newPrefix
+		if newPrefix != "" && strings.HasSuffix(prefix, ".") {
+			text += "."
+		}
+		edits = append(edits, textEdit{start: prefixLiteral.Start, end: prefixLiteral.End, text: text})
+	}
+	for i, literal := range names.Literals {
+		name := keys[i]
+		if newPrefix != "" {
+			name = strings.TrimPrefix(name, newPrefix+".")
+		}
+		if name != content[literal.Start:literal.End] {
+			edits = append(edits, textEdit{start: literal.Start, end: literal.End, text: name})
+		}
+	}
+	return edits
+}
+
+// commonKeyPrefix returns the longest run of leading segments shared by all
+// keys that leaves at least one segment of each key as its name
+func commonKeyPrefix(keys []string) string {
+	segments := utils.SplitPropertyKey(keys[0])
+	shared := len(segments) - 1
+	for _, key := range keys[1:] {
+		other := utils.SplitPropertyKey(key)
+		if len(other)-1 < shared {
+			shared = len(other) - 1
+		}
+		for i := 0; i < shared; i++ {
+			if other[i] != segments[i] {
+				shared = i
+				break
+			}
+		}
+	}
+	if shared <= 0 {
+		return ""
+	}
+	return strings.Join(segments[:shared], ".")
+}
+
+// compilePatterns compiles the old key and exception patterns on first use
+func (r *ChangeSpringPropertyKeyRecipe) compilePatterns() error {
+	if r.oldKeyPattern != nil {
+		return nil
+	}
+
+	oldKeyPattern, err := utils.CompileKeyPattern(r.OldPropertyKey, r.Regex)
+	if err != nil {
+		return fmt.Errorf("invalid old property key: %w", err)
+	}
+	exceptPatterns := make([]*utils.KeyPattern, 0, len(r.Except))
+	for _, except := range r.Except {
+		exceptPattern, err := utils.CompileKeyPattern(except, r.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid exception: %w", err)
+		}
+		exceptPatterns = append(exceptPatterns, exceptPattern)
+	}
+
+	r.oldKeyPattern = oldKeyPattern
+	r.exceptPatterns = exceptPatterns
+	return nil
+}
+
+// shouldTransformKey checks if a key matches the old key pattern and none of the exceptions
 func (r *ChangeSpringPropertyKeyRecipe) shouldTransformKey(key string) bool {
-	// Check if the key matches the old property key pattern
-	if !strings.HasPrefix(key, r.OldPropertyKey) {
+	subkey, ok := r.oldKeyPattern.Subkey(key)
+	if !ok {
 		return false
 	}
 
-	// Check exceptions
-	if len(r.Except) > 0 {
-		for _, except := range r.Except {
-			if strings.Contains(key, r.OldPropertyKey+"."+except) {
+	// Exceptions are matched against the part of the key below the old key
+	if subkey != "" {
+		for _, except := range r.exceptPatterns {
+			if except.Matches(subkey) {
 				return false
 			}
 		}
@@ -186,10 +522,16 @@ func (r *ChangeSpringPropertyKeyRecipe) shouldTransformKey(key string) bool {
 	return true
 }
 
+// renameReference returns the new key for a key referenced from a placeholder or expression
+func (r *ChangeSpringPropertyKeyRecipe) renameReference(key string) (string, bool) {
+	if !r.shouldTransformKey(key) {
+		return key, false
+	}
+	return r.transformPropertyKey(key), true
+}
+
 // transformPropertyKey transforms a property key from old to new
 func (r *ChangeSpringPropertyKeyRecipe) transformPropertyKey(key string) string {
-	if strings.HasPrefix(key, r.OldPropertyKey) {
-		return strings.Replace(key, r.OldPropertyKey, r.NewPropertyKey, 1)
-	}
-	return key
+	newKey, _ := r.oldKeyPattern.Replace(key, r.NewPropertyKey)
+	return newKey
 }
@@ -0,0 +1,99 @@
+package recipes
+
+import "testing"
+
+func TestChangePropertyKeyMovesYAMLEntries(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldKey   string
+		newKey   string
+		content  string
+		expected string
+	}{
+		{
+			name:     "shared root keeps its place",
+			oldKey:   "spring.redis.host",
+			newKey:   "spring.data.redis.host",
+			content:  "spring:\n  redis:\n    host: x\nserver:\n  port: 8080\n",
+			expected: "spring:\n  data:\n    redis:\n      host: x\nserver:\n  port: 8080\n",
+		},
+		{
+			name:     "missing trailing newline is kept",
+			oldKey:   "spring.redis.host",
+			newKey:   "spring.data.redis.host",
+			content:  "spring:\n  redis:\n    host: x\nserver:\n  port: 8080",
+			expected: "spring:\n  data:\n    redis:\n      host: x\nserver:\n  port: 8080",
+		},
+		{
+			name:     "emptied branch is pruned",
+			oldKey:   "spring.redis",
+			newKey:   "spring.data.redis",
+			content:  "spring:\n  application:\n    name: app\n  redis:\n    host: x\n    port: 6379\nserver:\n  port: 8080\n",
+			expected: "spring:\n  application:\n    name: app\n  data:\n    redis:\n      host: x\n      port: 6379\nserver:\n  port: 8080\n",
+		},
+		{
+			name:     "new root is appended",
+			oldKey:   "management.metrics.export.prometheus.enabled",
+			newKey:   "management.prometheus.metrics.export.enabled",
+			content:  "management:\n  metrics:\n    export:\n      prometheus:\n        enabled: true\nserver:\n  port: 8080\n",
+			expected: "management:\n  prometheus:\n    metrics:\n      export:\n        enabled: true\nserver:\n  port: 8080\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := NewChangeSpringPropertyKeyRecipe(tt.oldKey, tt.newKey, nil)
+			actual, warnings := runRecipe(t, recipe, "src/main/resources/application.yml", tt.content)
+			if actual != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", actual, tt.expected)
+			}
+			if len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %v", warnings)
+			}
+		})
+	}
+}
+
+func TestChangePropertyKeyBeneathItself(t *testing.T) {
+	// After the move the old key holds the moved node and still matches, so it
+	// must not be moved again
+	tests := []struct {
+		name     string
+		oldKey   string
+		newKey   string
+		content  string
+		expected string
+	}{
+		{
+			name:     "siblings stay in place",
+			oldKey:   "app.timeout",
+			newKey:   "app.timeout.read",
+			content:  "app:\n  timeout: 5s\n  name: x\n",
+			expected: "app:\n  name: x\n  timeout:\n    read: 5s\n",
+		},
+		{
+			name:     "subtree moves once",
+			oldKey:   "app.timeout",
+			newKey:   "app.timeout.read",
+			content:  "app:\n  timeout:\n    connect: 1s\n",
+			expected: "app:\n  timeout:\n    read:\n      connect: 1s\n",
+		},
+		{
+			name:     "root key",
+			oldKey:   "app",
+			newKey:   "app.legacy",
+			content:  "app:\n  a: 1\n  b: 2\n",
+			expected: "app:\n  legacy:\n    a: 1\n    b: 2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := NewChangeSpringPropertyKeyRecipe(tt.oldKey, tt.newKey, nil)
+			actual, _ := runRecipe(t, recipe, "src/main/resources/application.yml", tt.content)
+			if actual != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", actual, tt.expected)
+			}
+		})
+	}
+}
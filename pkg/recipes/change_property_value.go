@@ -0,0 +1,170 @@
+package recipes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// ChangeSpringPropertyValueRecipe changes the values of Spring properties in configuration files
+type ChangeSpringPropertyValueRecipe struct {
+	core.BaseRecipe
+	// PropertyKey is the key whose value is changed; it may be a key glob such as
+	// "server.error.*" and may be empty to consider every key
+	PropertyKey string
+	NewValue    string
+	// OldValue restricts the change to values equal to it, or matching it when Regex is set
+	OldValue string
+	// Regex interprets OldValue as a regular expression that must match the
+	// whole value, like Java's String.matches, and whose capture groups can be
+	// referenced from NewValue as $1, $2, ...
+	Regex bool
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
+
+	oldValuePattern *regexp.Regexp
+}
+
+// NewChangeSpringPropertyValueRecipe creates a new ChangeSpringPropertyValue recipe
+func NewChangeSpringPropertyValueRecipe(propertyKey, newValue, oldValue string, regex bool) *ChangeSpringPropertyValueRecipe {
+	return &ChangeSpringPropertyValueRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Change the value of a spring application property",
+			Description: "Change spring application property values existing in either Properties or YAML files.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
+		},
+		PropertyKey: propertyKey,
+		NewValue:    newValue,
+		OldValue:    oldValue,
+		Regex:       regex,
+	}
+}
+
+// Apply executes the recipe on the provided source file
+func (r *ChangeSpringPropertyValueRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+
+	if r.Regex && r.oldValuePattern == nil {
+		if r.OldValue == "" {
+			return sourceFile, fmt.Errorf("old value is required when regex is enabled")
+		}
+		pattern, err := regexp.Compile("^(?:" + r.OldValue + ")$")
+		if err != nil {
+			return sourceFile, fmt.Errorf("invalid old value pattern %q: %w", r.OldValue, err)
+		}
+		r.oldValuePattern = pattern
+	}
+
+	switch sourceFile.GetType() {
+	case core.Properties:
+		return r.applyToProperties(sourceFile)
+	case core.YAML:
+		return r.applyToYAML(ctx, sourceFile)
+	default:
+		return sourceFile, nil
+	}
+}
+
+// applyToProperties changes matching values in a properties file
+func (r *ChangeSpringPropertyValueRecipe) applyToProperties(sourceFile core.SourceFile) (core.SourceFile, error) {
+	doc := utils.ParseProperties(sourceFile.GetContent())
+	modified := false
+
+	for i := 0; i < len(doc.Entries); i++ {
+		entry := doc.Entries[i]
+		if !r.matchesKey(entry.Key) {
+			continue
+		}
+		value := utils.UnescapePropertiesValue(entry.Value)
+		if newValue, ok := r.transformValue(value); ok && newValue != value {
+			doc.SetValue(entry, utils.EscapePropertiesValue(newValue))
+			modified = true
+		}
+	}
+
+	if modified {
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
+
+// applyToYAML changes matching scalar values in a YAML file, including the
+// items of flow sequences
+func (r *ChangeSpringPropertyValueRecipe) applyToYAML(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	doc := utils.ParseYAML(sourceFile.GetContent())
+	modified := false
+
+	for i := 0; i < len(doc.Entries); i++ {
+		entry := doc.Entries[i]
+		if !entry.HasValue() {
+			continue
+		}
+		if r.matchesKey(entry.Path) {
+			if newValue, ok := r.transformValue(entry.Value); ok && newValue != entry.Value {
+				doc.SetValue(entry, utils.FormatYAMLScalar(newValue, entry.RawValue))
+				modified = true
+			}
+		} else if items, ok := entry.FlowSequenceItems(); ok {
+			if rawValue, changed := r.transformFlowItems(entry, items); changed {
+				doc.SetValue(entry, rawValue)
+				modified = true
+			}
+		}
+	}
+
+	logger := core.GetExecutionContext(ctx).Logger
+	for _, key := range uneditableFlowKeys(doc, r.matchesKey) {
+		logger.Warn("Cannot change %s in %s: it is set inside a flow collection", key, sourceFile.GetPath())
+	}
+
+	if modified {
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
+
+// transformFlowItems returns the flow sequence of an entry with the matching
+// items changed, and whether any changed
+func (r *ChangeSpringPropertyValueRecipe) transformFlowItems(entry *utils.YAMLEntry, items []utils.YAMLFlowItem) (string, bool) {
+	changed := false
+	raws := make([]string, len(items))
+	for i, item := range items {
+		raws[i] = item.Raw
+		if !r.matchesKey(flowItemKey(entry, i)) {
+			continue
+		}
+		if newValue, ok := r.transformValue(item.Value); ok && newValue != item.Value {
+			raws[i] = utils.FormatYAMLFlowScalar(newValue, item.Raw)
+			changed = true
+		}
+	}
+	return utils.FormatYAMLFlowSequence(raws, items, entry.RawValue), changed
+}
+
+// matchesKey checks if a property key is in scope of the recipe
+func (r *ChangeSpringPropertyValueRecipe) matchesKey(key string) bool {
+	return r.PropertyKey == "" || utils.MatchKeyGlob(key, r.PropertyKey)
+}
+
+// transformValue returns the new value for an existing value, and whether it should change
+func (r *ChangeSpringPropertyValueRecipe) transformValue(value string) (string, bool) {
+	switch {
+	case r.Regex:
+		if !r.oldValuePattern.MatchString(value) {
+			return value, false
+		}
+		return r.oldValuePattern.ReplaceAllString(value, r.NewValue), true
+	case r.OldValue != "":
+		return r.NewValue, value == r.OldValue
+	default:
+		return r.NewValue, r.PropertyKey != ""
+	}
+}
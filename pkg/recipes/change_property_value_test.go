@@ -0,0 +1,83 @@
+package recipes
+
+import "testing"
+
+func TestChangePropertyValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		oldValue string
+		newValue string
+		regex    bool
+		path     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "escaped old value matches",
+			key:      "app.separator",
+			oldValue: "x:y",
+			newValue: "x=y",
+			path:     "application.properties",
+			content:  "app.separator=x\\:y\n",
+			expected: "app.separator=x=y\n",
+		},
+		{
+			name:     "new value is escaped",
+			key:      "app.dir",
+			newValue: `C:\new`,
+			path:     "application.properties",
+			content:  "app.dir=C:\\\\old\n",
+			expected: "app.dir=C:\\\\new\n",
+		},
+		{
+			name:     "unchanged escaped value is left alone",
+			key:      "app.dir",
+			newValue: `C:\old`,
+			path:     "application.properties",
+			content:  "app.dir=C:\\\\old\n",
+			expected: "app.dir=C:\\\\old\n",
+		},
+		{
+			name:     "regex captures in properties",
+			key:      "app.url",
+			oldValue: `http://(.*)`,
+			newValue: "https://$1",
+			regex:    true,
+			path:     "application.properties",
+			content:  "app.url=http\\://example.com\n",
+			expected: "app.url=https://example.com\n",
+		},
+		{
+			name:     "regex must match the whole value",
+			oldValue: "true",
+			newValue: "false",
+			regex:    true,
+			path:     "application.properties",
+			content:  "a=true\nb=untrue-ish\n",
+			expected: "a=false\nb=untrue-ish\n",
+		},
+		{
+			name:     "regex must match the whole YAML value",
+			oldValue: "on-trace.*",
+			newValue: "on-param",
+			regex:    true,
+			path:     "application.yml",
+			content:  "server:\n  error:\n    include-stacktrace: on-trace-param\n    include-message: not-on-trace\n",
+			expected: "server:\n  error:\n    include-stacktrace: on-param\n    include-message: not-on-trace\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := NewChangeSpringPropertyValueRecipe(tt.key, tt.newValue, tt.oldValue, tt.regex)
+			actual, warnings := runRecipe(t, recipe, "src/main/resources/"+tt.path, tt.content)
+			if actual != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", actual, tt.expected)
+			}
+			if len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %v", warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package recipes
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// CommentOutSpringPropertyKeyRecipe comments out Spring properties that were removed without replacement
+type CommentOutSpringPropertyKeyRecipe struct {
+	core.BaseRecipe
+	PropertyKey string
+	Comment     string
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
+}
+
+// NewCommentOutSpringPropertyKeyRecipe creates a new CommentOutSpringPropertyKey recipe
+func NewCommentOutSpringPropertyKeyRecipe(propertyKey, comment string) *CommentOutSpringPropertyKeyRecipe {
+	return &CommentOutSpringPropertyKeyRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Comment out Spring properties",
+			Description: "Add comment to specified Spring properties, and comment out the property, " +
+				"including the environment variables of Dockerfiles, .env files, docker-compose files and Kubernetes manifests.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML, core.Dockerfile, core.DotEnv},
+		},
+		PropertyKey: propertyKey,
+		Comment:     comment,
+	}
+}
+
+// Apply executes the recipe on the provided source file
+func (r *CommentOutSpringPropertyKeyRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	if sourceFile.GetConfigKind() == core.DeploymentConfig {
+		return r.applyToEnvironment(sourceFile)
+	}
+
+	if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+
+	switch sourceFile.GetType() {
+	case core.Properties:
+		return r.applyToProperties(sourceFile)
+	case core.YAML:
+		return r.applyToYAML(sourceFile)
+	default:
+		return sourceFile, nil
+	}
+}
+
+// applyToProperties comments out the key and its subkeys in a properties file
+func (r *CommentOutSpringPropertyKeyRecipe) applyToProperties(sourceFile core.SourceFile) (core.SourceFile, error) {
+	doc := utils.ParseProperties(sourceFile.GetContent())
+	modified := false
+
+	keys := propertiesKeys(doc)
+	for {
+		entry := r.findProperty(doc)
+		if entry == nil {
+			break
+		}
+
+		commented := make([]string, 0, entry.End-entry.Line+1)
+		if r.needsHeader(doc.Lines, entry.Line, "") {
+			commented = append(commented, "# "+r.Comment)
+		}
+		for _, line := range doc.Lines[entry.Line:entry.End] {
+			commented = append(commented, "# "+line)
+		}
+
+		doc.RemoveLines(entry.Line, entry.End)
+		doc.InsertLines(entry.Line, commented...)
+		modified = true
+	}
+
+	if modified {
+		renumberPropertyLists(doc, keys)
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
+
+// applyToYAML comments out the key and its whole subtree in a YAML file
+func (r *CommentOutSpringPropertyKeyRecipe) applyToYAML(sourceFile core.SourceFile) (core.SourceFile, error) {
+	doc := utils.ParseYAML(sourceFile.GetContent())
+	modified := false
+
+	// Bottom up, so that commenting out a sequence item does not renumber the
+	// items still to be commented out
+	matches := findOutermostYAMLEntries(doc, func(entry *utils.YAMLEntry) bool {
+		return utils.IsKeyOrSubkey(entry.Path, r.PropertyKey)
+	})
+	for i := len(matches) - 1; i >= 0; i-- {
+		entry := findYAMLEntryAt(doc, matches[i].Line, matches[i].Indent)
+		for entry.SharesItemLine() && len(entry.Parent.Children) == 1 {
+			entry = entry.Parent
+		}
+		if entry.SharesItemLine() {
+			moveYAMLItemDash(doc, entry)
+		}
+
+		indent := strings.Repeat(" ", entry.Indent)
+		commented := make([]string, 0, entry.End-entry.Line+1)
+		if r.needsHeader(doc.Lines, entry.Line, indent) {
+			commented = append(commented, indent+"# "+r.Comment)
+		}
+		for _, line := range doc.Lines[entry.Line:entry.End] {
+			if strings.TrimSpace(line) == "" {
+				commented = append(commented, line)
+				continue
+			}
+			// Subtree lines are at least as deep as the key, so the relative
+			// indentation survives behind the comment marker
+			commented = append(commented, indent+"# "+line[entry.Indent:])
+		}
+
+		doc.RemoveLines(entry.Line, entry.End)
+		doc.InsertLines(entry.Line, commented...)
+		modified = true
+	}
+
+	if modified {
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
+
+// applyToEnvironment comments out the environment variables that bind to the
+// key or its subkeys in a deployment file
+func (r *CommentOutSpringPropertyKeyRecipe) applyToEnvironment(sourceFile core.SourceFile) (core.SourceFile, error) {
+	lines := strings.Split(sourceFile.GetContent(), "\n")
+	modified := false
+
+	for {
+		variable := findEnvironmentVariable(lines, sourceFile.GetType(), func(name string) bool {
+			return utils.IsKeyOrSubkey(name, r.PropertyKey)
+		})
+		if variable == nil {
+			break
+		}
+
+		header := ""
+		if r.needsHeader(lines, variable.Start, leadingWhitespace(lines[variable.Start])) {
+			header = r.Comment
+		}
+		lines = commentOutEnvironmentVariable(lines, variable, header)
+		modified = true
+	}
+
+	if modified {
+		sourceFile.SetContent(strings.Join(lines, "\n"))
+	}
+
+	return sourceFile, nil
+}
+
+// findProperty returns the first active entry that is the key or one of its subkeys
+func (r *CommentOutSpringPropertyKeyRecipe) findProperty(doc *utils.PropertiesDocument) *utils.PropertiesEntry {
+	for _, entry := range doc.Entries {
+		if utils.IsKeyOrSubkey(entry.Key, r.PropertyKey) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// needsHeader checks whether the explanatory comment still has to be inserted above
+// the line; it is skipped when empty or already present from an earlier run
+func (r *CommentOutSpringPropertyKeyRecipe) needsHeader(lines []string, line int, indent string) bool {
+	if r.Comment == "" {
+		return false
+	}
+	header := indent + "# " + r.Comment
+	for i := line - 1; i >= 0; i-- {
+		if lines[i] == header {
+			return false
+		}
+		// Only look through the block of comments directly above the line
+		if !strings.HasPrefix(strings.TrimSpace(lines[i]), "#") {
+			break
+		}
+	}
+	return true
+}
@@ -0,0 +1,152 @@
+package recipes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// acceptsConfigKind reports whether a property recipe may modify the file.
+// Properties and YAML files are only touched when they are Spring configuration,
+// unless includeNonSpringConfig is set; other file types are always accepted.
+func acceptsConfigKind(sourceFile core.SourceFile, includeNonSpringConfig bool) bool {
+	switch sourceFile.GetType() {
+	case core.Properties, core.YAML:
+		return includeNonSpringConfig || sourceFile.GetConfigKind().IsSpringConfig()
+	default:
+		return true
+	}
+}
+
+// expandRemoval widens the line range [start, end) that is about to be removed
+// by one trailing blank line when the removal would otherwise leave two blank
+// lines next to each other, or a blank line at the top of the file
+func expandRemoval(lines []string, start, end int) (int, int) {
+	blankBefore := start == 0 || strings.TrimSpace(lines[start-1]) == ""
+	if blankBefore && end < len(lines)-1 && strings.TrimSpace(lines[end]) == "" {
+		end++
+	}
+	return start, end
+}
+
+// reindentLines shifts every non-blank line by delta columns
+func reindentLines(lines []string, delta int) []string {
+	shifted := make([]string, len(lines))
+	for i, line := range lines {
+		switch {
+		case strings.TrimSpace(line) == "" || delta == 0:
+			shifted[i] = line
+		case delta > 0:
+			shifted[i] = strings.Repeat(" ", delta) + line
+		default:
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if indent > -delta {
+				indent = -delta
+			}
+			shifted[i] = line[indent:]
+		}
+	}
+	return shifted
+}
+
+// textEdit replaces the text at [start, end) of a file
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// applyTextEdits applies non-overlapping edits to content
+func applyTextEdits(content string, edits []textEdit) string {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	var b strings.Builder
+	last := 0
+	for _, edit := range edits {
+		if edit.start < last {
+			continue
+		}
+		b.WriteString(content[last:edit.start])
+		b.WriteString(edit.text)
+		last = edit.end
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// keepTrailingNewline ends content with a newline only when original does
+func keepTrailingNewline(content, original string) string {
+	if strings.HasSuffix(original, "\n") {
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content
+	}
+	return strings.TrimRight(content, "\n")
+}
+
+// sourceFileSet tracks the files seen by a scanning recipe by path, in order
+type sourceFileSet struct {
+	paths  []string
+	byPath map[string]core.SourceFile
+}
+
+// newSourceFileSet creates a set holding the given files
+func newSourceFileSet(sourceFiles []core.SourceFile) *sourceFileSet {
+	set := &sourceFileSet{byPath: make(map[string]core.SourceFile, len(sourceFiles))}
+	for _, sourceFile := range sourceFiles {
+		set.add(sourceFile)
+	}
+	return set
+}
+
+// add inserts or replaces a file in the set
+func (s *sourceFileSet) add(sourceFile core.SourceFile) {
+	path := sourceFile.GetPath()
+	if _, ok := s.byPath[path]; !ok {
+		s.paths = append(s.paths, path)
+	}
+	s.byPath[path] = sourceFile
+}
+
+// get returns the file at the path, or nil when it is not part of the set
+func (s *sourceFileSet) get(path string) core.SourceFile {
+	return s.byPath[path]
+}
+
+// getOrCreate returns the file at the path, loading it from disk when it exists
+// outside the set, or creating an empty file with the given encoding otherwise
+func (s *sourceFileSet) getOrCreate(path string, encoding core.Encoding) core.SourceFile {
+	if sourceFile, ok := s.byPath[path]; ok {
+		return sourceFile
+	}
+	sourceFile, err := utils.LoadSourceFile(path)
+	if err != nil {
+		sourceFile = utils.NewSourceFile(path, "", encoding)
+	}
+	s.add(sourceFile)
+	return sourceFile
+}
+
+// remove drops the file at the path from the set, so that the runner deletes it
+func (s *sourceFileSet) remove(path string) {
+	if _, ok := s.byPath[path]; !ok {
+		return
+	}
+	delete(s.byPath, path)
+	for i, p := range s.paths {
+		if p == path {
+			s.paths = append(s.paths[:i], s.paths[i+1:]...)
+			break
+		}
+	}
+}
+
+// list returns the files of the set in insertion order
+func (s *sourceFileSet) list() []core.SourceFile {
+	sourceFiles := make([]core.SourceFile, 0, len(s.paths))
+	for _, path := range s.paths {
+		sourceFiles = append(sourceFiles, s.byPath[path])
+	}
+	return sourceFiles
+}
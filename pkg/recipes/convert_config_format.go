@@ -0,0 +1,154 @@
+package recipes
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// PropertiesToYamlRecipe converts Spring .properties files into .yml files
+type PropertiesToYamlRecipe struct {
+	core.BaseRecipe
+	// IncludeNonSpringConfig also converts properties files that are not Spring configuration
+	IncludeNonSpringConfig bool
+}
+
+// NewPropertiesToYamlRecipe creates a new PropertiesToYaml recipe
+func NewPropertiesToYamlRecipe() *PropertiesToYamlRecipe {
+	return &PropertiesToYamlRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Convert Spring properties files to YAML",
+			Description: "Convert `.properties` configuration into `.yml` files, keeping comments, list indexes, " +
+				"bracketed map keys and `#---` documents. When the YAML file already exists the properties " +
+				"are merged into it, taking precedence as they do at runtime.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
+		},
+	}
+}
+
+// Apply cannot replace the file on its own, so the file is returned unchanged;
+// the runner uses ApplyAll instead
+func (r *PropertiesToYamlRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	return sourceFile, nil
+}
+
+// ApplyAll replaces every properties file in the set with a YAML file
+func (r *PropertiesToYamlRecipe) ApplyAll(ctx context.Context, sourceFiles []core.SourceFile) ([]core.SourceFile, error) {
+	logger := core.GetExecutionContext(ctx).Logger
+	files := newSourceFileSet(sourceFiles)
+
+	for _, sourceFile := range sourceFiles {
+		if sourceFile.GetType() != core.Properties || !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+			continue
+		}
+
+		converted, err := utils.ConvertPropertiesToYAML(sourceFile.GetContent())
+		if err != nil {
+			logger.Warn("Cannot convert %s to YAML: %v", sourceFile.GetPath(), err)
+			continue
+		}
+
+		encoding := sourceFile.GetEncoding()
+		encoding.Charset = core.UTF8
+		yamlPath := convertedFilePath(files, sourceFile.GetPath(), ".yml", ".yaml")
+		yamlFile := files.getOrCreate(yamlPath, encoding)
+
+		if strings.TrimSpace(yamlFile.GetContent()) == "" {
+			yamlFile.SetContent(converted)
+		} else {
+			// Properties take precedence over YAML in the same location
+			target := utils.ParseYAML(converted)
+			for _, conflict := range mergeYAMLDocuments(target, utils.ParseYAML(yamlFile.GetContent())) {
+				logger.Warn("%s sets %s to %q, which takes precedence over %q in %s",
+					sourceFile.GetPath(), conflict.Path, conflict.ExistingValue, conflict.IgnoredValue, yamlPath)
+			}
+			yamlFile.SetContent(ensureTrailingNewline(target.String()))
+		}
+
+		files.remove(sourceFile.GetPath())
+	}
+
+	return files.list(), nil
+}
+
+// YamlToPropertiesRecipe converts Spring YAML files into .properties files
+type YamlToPropertiesRecipe struct {
+	core.BaseRecipe
+	// IncludeNonSpringConfig also converts YAML files that are not Spring configuration
+	IncludeNonSpringConfig bool
+}
+
+// NewYamlToPropertiesRecipe creates a new YamlToProperties recipe
+func NewYamlToPropertiesRecipe() *YamlToPropertiesRecipe {
+	return &YamlToPropertiesRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Convert Spring YAML files to properties",
+			Description: "Convert `.yml` configuration into `.properties` files, flattening sequences into indexed keys " +
+				"and documents into `#---` sections while keeping comments. When the properties file already " +
+				"exists the YAML is merged into it, and the existing properties take precedence as they do at runtime.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
+		},
+	}
+}
+
+// Apply cannot replace the file on its own, so the file is returned unchanged;
+// the runner uses ApplyAll instead
+func (r *YamlToPropertiesRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	return sourceFile, nil
+}
+
+// ApplyAll replaces every YAML file in the set with a properties file
+func (r *YamlToPropertiesRecipe) ApplyAll(ctx context.Context, sourceFiles []core.SourceFile) ([]core.SourceFile, error) {
+	logger := core.GetExecutionContext(ctx).Logger
+	files := newSourceFileSet(sourceFiles)
+
+	for _, sourceFile := range sourceFiles {
+		if sourceFile.GetType() != core.YAML || !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+			continue
+		}
+
+		converted, err := utils.ConvertYAMLToProperties(sourceFile.GetContent())
+		if err != nil {
+			logger.Warn("Cannot convert %s to properties: %v", sourceFile.GetPath(), err)
+			continue
+		}
+
+		// Spring Boot reads properties files as ISO-8859-1
+		encoding := sourceFile.GetEncoding()
+		encoding.Charset = core.ISO88591
+		encoding.BOM = false
+		propertiesPath := convertedFilePath(files, sourceFile.GetPath(), ".properties")
+		propertiesFile := files.getOrCreate(propertiesPath, encoding)
+
+		if strings.TrimSpace(propertiesFile.GetContent()) == "" {
+			propertiesFile.SetContent(converted)
+		} else {
+			merged, conflicts := mergePropertiesDocuments(propertiesFile.GetContent(), converted)
+			for _, conflict := range conflicts {
+				logger.Warn("%s sets %s to %q, which takes precedence over %q in %s",
+					propertiesPath, conflict.Path, conflict.ExistingValue, conflict.IgnoredValue, sourceFile.GetPath())
+			}
+			propertiesFile.SetContent(merged)
+		}
+
+		files.remove(sourceFile.GetPath())
+	}
+
+	return files.list(), nil
+}
+
+// convertedFilePath returns the path of the converted file next to the source,
+// preferring an existing file with one of the extensions
+func convertedFilePath(files *sourceFileSet, sourcePath string, extensions ...string) string {
+	base := strings.TrimSuffix(sourcePath, filepath.Ext(sourcePath))
+	for _, ext := range extensions {
+		path := base + ext
+		if files.get(path) != nil || utils.FileExists(path) {
+			return path
+		}
+	}
+	return base + extensions[0]
+}
@@ -0,0 +1,281 @@
+package recipes
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// DeleteSpringPropertyRecipe deletes Spring properties from configuration files
+type DeleteSpringPropertyRecipe struct {
+	core.BaseRecipe
+	// PropertyKey is the key to delete, or a key glob such as "management.endpoint.configprops.*".
+	// Subkeys and YAML subtrees of a matching key are deleted with it.
+	PropertyKey string
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
+}
+
+// NewDeleteSpringPropertyRecipe creates a new DeleteSpringProperty recipe
+func NewDeleteSpringPropertyRecipe(propertyKey string) *DeleteSpringPropertyRecipe {
+	return &DeleteSpringPropertyRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Delete a spring configuration property",
+			Description: "Delete a spring configuration property from any configuration file that contains a matching key, " +
+				"from the test properties of @SpringBootTest, @TestPropertySource and DynamicPropertyRegistry, " +
+				"and from the environment variables of Dockerfiles, .env files, docker-compose files and Kubernetes manifests.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML, core.Java, core.Kotlin, core.Dockerfile, core.DotEnv},
+		},
+		PropertyKey: propertyKey,
+	}
+}
+
+// Apply executes the recipe on the provided source file
+func (r *DeleteSpringPropertyRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	if sourceFile.GetConfigKind() == core.DeploymentConfig {
+		return r.applyToEnvironment(sourceFile)
+	}
+
+	if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+
+	switch sourceFile.GetType() {
+	case core.Properties:
+		return r.applyToProperties(sourceFile)
+	case core.YAML:
+		return r.applyToYAML(ctx, sourceFile)
+	case core.Java, core.Kotlin:
+		return r.applyToSource(sourceFile)
+	default:
+		return sourceFile, nil
+	}
+}
+
+// applyToProperties removes matching entries and their attached comments
+func (r *DeleteSpringPropertyRecipe) applyToProperties(sourceFile core.SourceFile) (core.SourceFile, error) {
+	doc := utils.ParseProperties(sourceFile.GetContent())
+	modified := false
+
+	keys := propertiesKeys(doc)
+	for {
+		entry := r.findProperty(doc)
+		if entry == nil {
+			break
+		}
+		start, end := expandRemoval(doc.Lines, doc.LeadingCommentStart(entry), entry.End)
+		doc.RemoveLines(start, end)
+		modified = true
+	}
+
+	if modified {
+		renumberPropertyLists(doc, keys)
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
+
+// applyToYAML removes matching subtrees and prunes the parent mappings they
+// leave empty. Matching items of flow sequences are removed from the sequence.
+func (r *DeleteSpringPropertyRecipe) applyToYAML(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	doc := utils.ParseYAML(sourceFile.GetContent())
+	modified := false
+
+	// Bottom up, so that removing a sequence item does not renumber the items
+	// still to be removed, and the lines of earlier matches stay in place
+	matches := findOutermostYAMLEntries(doc, func(entry *utils.YAMLEntry) bool {
+		return utils.MatchKeyOrSubkey(entry.Path, r.PropertyKey)
+	})
+	for i := len(matches) - 1; i >= 0; i-- {
+		removeYAMLEntry(doc, findYAMLEntryAt(doc, matches[i].Line, matches[i].Indent))
+		modified = true
+	}
+
+	// Each flow sequence once, since removing an item renumbers the following ones
+	var sequences []*utils.YAMLEntry
+	for _, entry := range doc.Entries {
+		if _, ok := entry.FlowSequenceItems(); ok {
+			sequences = append(sequences, entry)
+		}
+	}
+	for i := len(sequences) - 1; i >= 0; i-- {
+		if r.removeFlowItems(doc, findYAMLEntryAt(doc, sequences[i].Line, sequences[i].Indent)) {
+			modified = true
+		}
+	}
+
+	logger := core.GetExecutionContext(ctx).Logger
+	for _, key := range uneditableFlowKeys(doc, func(key string) bool { return utils.MatchKeyOrSubkey(key, r.PropertyKey) }) {
+		logger.Warn("Cannot delete %s from %s: it is set inside a flow collection", key, sourceFile.GetPath())
+	}
+
+	if modified {
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
+
+// removeFlowItems removes the matching items of a flow sequence, together with
+// the sequence when no item is left, and reports whether there were any
+func (r *DeleteSpringPropertyRecipe) removeFlowItems(doc *utils.YAMLDocument, entry *utils.YAMLEntry) bool {
+	items, _ := entry.FlowSequenceItems()
+	var kept []string
+	for i, item := range items {
+		if !utils.MatchKeyOrSubkey(flowItemKey(entry, i), r.PropertyKey) {
+			kept = append(kept, item.Raw)
+		}
+	}
+	switch {
+	case len(kept) == len(items):
+		return false
+	case len(kept) == 0:
+		removeYAMLEntry(doc, entry)
+	default:
+		doc.SetValue(entry, utils.FormatYAMLFlowSequence(kept, items, entry.RawValue))
+	}
+	return true
+}
+
+// applyToSource removes matching properties defined inline by Java or Kotlin tests
+func (r *DeleteSpringPropertyRecipe) applyToSource(sourceFile core.SourceFile) (core.SourceFile, error) {
+	content := sourceFile.GetContent()
+	properties := findTestProperties(content, sourceFile.GetType())
+
+	var removed []inlineTestProperty
+	for _, property := range properties {
+		if utils.MatchKeyOrSubkey(property.key, r.PropertyKey) {
+			removed = append(removed, property)
+		}
+	}
+
+	if len(removed) > 0 {
+		sourceFile.SetContent(removeTestProperties(content, properties, removed))
+	}
+
+	return sourceFile, nil
+}
+
+// applyToEnvironment removes the environment variables that bind to matching
+// properties from a deployment file
+func (r *DeleteSpringPropertyRecipe) applyToEnvironment(sourceFile core.SourceFile) (core.SourceFile, error) {
+	lines := strings.Split(sourceFile.GetContent(), "\n")
+	modified := false
+
+	for {
+		variable := findEnvironmentVariable(lines, sourceFile.GetType(), func(name string) bool {
+			return utils.MatchKeyOrSubkey(name, r.PropertyKey)
+		})
+		if variable == nil {
+			break
+		}
+		lines = removeEnvironmentVariable(lines, variable)
+		modified = true
+	}
+
+	if modified {
+		sourceFile.SetContent(strings.Join(lines, "\n"))
+	}
+
+	return sourceFile, nil
+}
+
+// findProperty returns the first entry matching the key pattern
+func (r *DeleteSpringPropertyRecipe) findProperty(doc *utils.PropertiesDocument) *utils.PropertiesEntry {
+	for _, entry := range doc.Entries {
+		if utils.MatchKeyOrSubkey(entry.Key, r.PropertyKey) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// findOutermostYAMLEntries returns the entries that match, in file order,
+// leaving out those beneath another match
+func findOutermostYAMLEntries(doc *utils.YAMLDocument, match func(*utils.YAMLEntry) bool) []*utils.YAMLEntry {
+	var matches []*utils.YAMLEntry
+	for _, entry := range doc.Entries {
+		if len(matches) > 0 && isYAMLDescendant(entry, matches[len(matches)-1]) {
+			continue
+		}
+		if match(entry) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches
+}
+
+// isYAMLDescendant reports whether entry lies beneath ancestor
+func isYAMLDescendant(entry, ancestor *utils.YAMLEntry) bool {
+	for parent := entry.Parent; parent != nil; parent = parent.Parent {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// findYAMLEntryAt returns the entry whose key or dash is at the given line and column
+func findYAMLEntryAt(doc *utils.YAMLDocument, line, indent int) *utils.YAMLEntry {
+	for _, entry := range doc.Entries {
+		if entry.Line == line && entry.Indent == indent {
+			return entry
+		}
+	}
+	return nil
+}
+
+// removeYAMLEntry deletes an entry with its subtree and attached comments, then
+// deletes every ancestor mapping that no longer has any content. A sequence item
+// goes with the last of its entries; removing the entry on the line of the dash
+// moves the dash to the next entry of the item.
+func removeYAMLEntry(doc *utils.YAMLDocument, entry *utils.YAMLEntry) {
+	for entry != nil {
+		for entry.SharesItemLine() && len(entry.Parent.Children) == 1 {
+			entry = entry.Parent
+		}
+		var parentPath string
+		if entry.Parent != nil {
+			parentPath = entry.Parent.Path
+		}
+		document := entry.Document
+
+		start := doc.LeadingCommentStart(entry)
+		if entry.SharesItemLine() {
+			// Comments above the dash belong to the item
+			start = entry.Line
+			moveYAMLItemDash(doc, entry)
+		}
+		start, end := expandRemoval(doc.Lines, start, entry.End)
+		doc.RemoveLines(start, end)
+
+		entry = nil
+		if parentPath != "" {
+			if parent := doc.FindInDocument(parentPath, document); parent != nil && isEmptyYAMLMapping(parent) {
+				entry = parent
+			}
+		}
+	}
+}
+
+// moveYAMLItemDash moves the dash of a sequence item from the line of its first
+// entry to the line of the second, so that the first entry has lines of its own.
+// The entry and its lines keep their position; the document is re-indexed.
+func moveYAMLItemDash(doc *utils.YAMLDocument, entry *utils.YAMLEntry) {
+	item := entry.Parent
+	next := item.Children[1]
+	line := doc.Lines[entry.Line]
+	doc.Lines[entry.Line] = line[:item.Indent] + " " + line[item.Indent+1:]
+	line = doc.Lines[next.Line]
+	doc.Lines[next.Line] = line[:item.Indent] + "-" + line[item.Indent+1:]
+	doc.SetLines(doc.Lines)
+}
+
+// isEmptyYAMLMapping reports whether an entry has neither a value nor any nested content
+func isEmptyYAMLMapping(entry *utils.YAMLEntry) bool {
+	return entry.RawValue == "" && len(entry.Children) == 0 && entry.End == entry.Line+1
+}
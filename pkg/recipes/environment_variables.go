@@ -0,0 +1,164 @@
+package recipes
+
+import (
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// renameEnvironmentVariables renames the environment variables of a deployment
+// file for which rename returns a new name, reporting whether any changed
+func renameEnvironmentVariables(sourceFile core.SourceFile, rename func(name string) (string, bool)) bool {
+	lines := strings.Split(sourceFile.GetContent(), "\n")
+	variables := utils.FindEnvironmentVariables(lines, sourceFile.GetType())
+	modified := false
+
+	// Later names first, so that earlier offsets on the same line stay valid
+	for i := len(variables) - 1; i >= 0; i-- {
+		variable := variables[i]
+		newName, ok := rename(variable.Name)
+		if !ok || newName == variable.Name {
+			continue
+		}
+		line := lines[variable.Line]
+		lines[variable.Line] = line[:variable.NameStart] + newName + line[variable.NameEnd:]
+		modified = true
+	}
+
+	if modified {
+		sourceFile.SetContent(strings.Join(lines, "\n"))
+	}
+	return modified
+}
+
+// findEnvironmentVariable returns the first environment variable of the lines
+// whose name satisfies match
+func findEnvironmentVariable(lines []string, fileType core.FileType, match func(name string) bool) *utils.EnvironmentVariable {
+	for _, variable := range utils.FindEnvironmentVariables(lines, fileType) {
+		if match(variable.Name) {
+			return &variable
+		}
+	}
+	return nil
+}
+
+// removeEnvironmentVariable deletes the definition of a variable with the
+// comments directly above it, and an "env:" or "environment:" block it leaves empty.
+// Of a Dockerfile ENV instruction setting several variables only "NAME=value" is removed.
+func removeEnvironmentVariable(lines []string, variable *utils.EnvironmentVariable) []string {
+	if variable.Shared {
+		return removeDockerfileDefinition(lines, variable)
+	}
+
+	start := variable.Start
+	for start > 0 && strings.HasPrefix(strings.TrimSpace(lines[start-1]), "#") &&
+		(variable.Block < 0 || start-1 > variable.Block) {
+		start--
+	}
+	start, end := expandRemoval(lines, start, variable.End)
+	lines = append(lines[:start], lines[end:]...)
+
+	if variable.Block >= 0 && isEmptyEnvironmentBlock(lines, variable.Block) {
+		lines = append(lines[:variable.Block], lines[variable.Block+1:]...)
+	}
+	return lines
+}
+
+// removeDockerfileDefinition removes "NAME=value" from an ENV instruction that
+// sets other variables too, dropping a continuation line it leaves empty
+func removeDockerfileDefinition(lines []string, variable *utils.EnvironmentVariable) []string {
+	line := lines[variable.Line]
+	start, end := variable.DefinitionStart, variable.DefinitionEnd
+	for end < len(line) && (line[end] == ' ' || line[end] == '\t') {
+		end++
+	}
+	if end == len(line) {
+		for start > 0 && (line[start-1] == ' ' || line[start-1] == '\t') {
+			start--
+		}
+	}
+	lines[variable.Line] = line[:start] + line[end:]
+
+	last := variable.End - 1
+	if remaining := strings.TrimSpace(lines[variable.Line]); variable.Line > variable.Start && (remaining == "" || remaining == "\\") {
+		lines = append(lines[:variable.Line], lines[variable.Line+1:]...)
+		last--
+	}
+	// A bare "ENV \" takes over the next line of the instruction
+	if fields := strings.Fields(lines[variable.Start]); len(fields) == 2 && fields[1] == "\\" && last > variable.Start {
+		lines[variable.Start] = strings.TrimSuffix(strings.TrimRight(lines[variable.Start], " \t"), "\\") +
+			strings.TrimLeft(lines[variable.Start+1], " \t")
+		lines = append(lines[:variable.Start+1], lines[variable.Start+2:]...)
+		last--
+	}
+	// The instruction must not continue into the next one
+	if trimmed := strings.TrimRight(lines[last], " \t"); strings.HasSuffix(trimmed, "\\") {
+		lines[last] = strings.TrimRight(strings.TrimSuffix(trimmed, "\\"), " \t")
+	}
+	return lines
+}
+
+// isEmptyEnvironmentBlock reports whether the block key on line block has no items left
+func isEmptyEnvironmentBlock(lines []string, block int) bool {
+	key := strings.TrimLeft(lines[block], " ")
+	if strings.HasPrefix(key, "- ") {
+		key = strings.TrimLeft(key[1:], " ")
+	}
+	keyIndent := len(lines[block]) - len(key)
+	for _, line := range lines[block+1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		return indent < keyIndent || (indent == keyIndent && !strings.HasPrefix(trimmed, "-"))
+	}
+	return true
+}
+
+// commentOutEnvironmentVariable comments out the definition of a variable below
+// a header comment, unless header is empty. Of a Dockerfile ENV instruction
+// setting several variables, "NAME=value" moves into a commented ENV instruction.
+func commentOutEnvironmentVariable(lines []string, variable *utils.EnvironmentVariable, header string) []string {
+	if variable.Shared {
+		definition := lines[variable.Line][variable.DefinitionStart:variable.DefinitionEnd]
+		lines = removeDockerfileDefinition(lines, variable)
+		indent := leadingWhitespace(lines[variable.Start])
+		commented := []string{indent + "# ENV " + definition}
+		if header != "" {
+			commented = append([]string{indent + "# " + header}, commented...)
+		}
+		return insertLines(lines, variable.Start, commented)
+	}
+
+	indent := leadingWhitespace(lines[variable.Start])
+	var commented []string
+	if header != "" {
+		commented = append(commented, indent+"# "+header)
+	}
+	for _, line := range lines[variable.Start:variable.End] {
+		switch {
+		case strings.TrimSpace(line) == "":
+			commented = append(commented, line)
+		case strings.HasPrefix(line, indent):
+			commented = append(commented, indent+"# "+line[len(indent):])
+		default:
+			commented = append(commented, "# "+line)
+		}
+	}
+
+	lines = append(lines[:variable.Start], lines[variable.End:]...)
+	return insertLines(lines, variable.Start, commented)
+}
+
+// insertLines inserts lines before index at
+func insertLines(lines []string, at int, inserted []string) []string {
+	rest := append([]string{}, lines[at:]...)
+	return append(append(lines[:at], inserted...), rest...)
+}
+
+// leadingWhitespace returns the spaces and tabs a line starts with
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -0,0 +1,155 @@
+package recipes
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// ExpandPropertiesRecipe rewrites dotted YAML keys such as "spring.application.name"
+// into nested mappings, merging them into parents that already exist
+type ExpandPropertiesRecipe struct {
+	core.BaseRecipe
+	// SourceFileMask optionally restricts which YAML files are expanded, e.g. "**/application*.yml"
+	SourceFileMask string
+	// IncludeNonSpringConfig also processes YAML files that are not Spring configuration
+	IncludeNonSpringConfig bool
+}
+
+// NewExpandPropertiesRecipe creates a new ExpandProperties recipe
+func NewExpandPropertiesRecipe(sourceFileMask string) *ExpandPropertiesRecipe {
+	return &ExpandPropertiesRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Expand Spring YAML properties",
+			Description: "Expand YAML properties to not use the dot syntax shortcut.",
+			SourceTypes: []core.FileType{core.YAML},
+		},
+		SourceFileMask: sourceFileMask,
+	}
+}
+
+// Apply executes the recipe on the provided source file
+func (r *ExpandPropertiesRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	if sourceFile.GetType() != core.YAML || !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+	if r.SourceFileMask != "" {
+		if matched, _ := utils.MatchGlob(sourceFile.GetPath(), r.SourceFileMask); !matched {
+			return sourceFile, nil
+		}
+	}
+
+	doc := utils.ParseYAML(sourceFile.GetContent())
+	modified := false
+
+	for {
+		if entry := findDottedYAMLEntry(doc); entry != nil {
+			expandYAMLEntry(doc, entry)
+			modified = true
+			continue
+		}
+		if first, second := findDuplicateYAMLEntries(doc); first != nil {
+			mergeYAMLEntries(doc, first, second)
+			modified = true
+			continue
+		}
+		break
+	}
+
+	if modified {
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
+
+// findDottedYAMLEntry returns the first entry whose key uses the dot syntax shortcut
+func findDottedYAMLEntry(doc *utils.YAMLDocument) *utils.YAMLEntry {
+	for _, entry := range doc.Entries {
+		if !strings.Contains(entry.Key, ".") || strings.Contains(entry.Key, "[") {
+			continue
+		}
+		// Keys of map-valued properties are user-defined names like logger categories
+		if utils.IsMapValuedKey(entry.Path) || (entry.Parent != nil && utils.IsMapValuedProperty(entry.Parent.Path)) {
+			continue
+		}
+		return entry
+	}
+	return nil
+}
+
+// expandYAMLEntry replaces "a.b.c: value" with a nested mapping, shifting the subtree along
+func expandYAMLEntry(doc *utils.YAMLDocument, entry *utils.YAMLEntry) {
+	unit := doc.IndentUnit()
+	segments := utils.SplitPropertyKey(entry.Key)
+	line := doc.Lines[entry.Line]
+
+	expanded := make([]string, 0, len(segments)+entry.End-entry.Line)
+	for i, segment := range segments {
+		indent := strings.Repeat(" ", entry.Indent+i*unit)
+		if i == 0 {
+			// Keeps the dash of a sequence item
+			indent = line[:entry.KeyStart]
+		}
+		if i < len(segments)-1 {
+			expanded = append(expanded, indent+utils.FormatYAMLKey(segment)+":")
+		} else {
+			// The last segment keeps the value and any trailing comment
+			expanded = append(expanded, indent+utils.FormatYAMLKey(segment)+line[entry.KeyEnd:])
+		}
+	}
+	expanded = append(expanded, reindentLines(doc.Lines[entry.Line+1:entry.End], (len(segments)-1)*unit)...)
+
+	doc.RemoveLines(entry.Line, entry.End)
+	doc.InsertLines(entry.Line, expanded...)
+}
+
+// findDuplicateYAMLEntries returns the first pair of sibling mappings sharing a key
+// that can be merged, i.e. neither of them holds an inline value
+func findDuplicateYAMLEntries(doc *utils.YAMLDocument) (*utils.YAMLEntry, *utils.YAMLEntry) {
+	for i, first := range doc.Entries {
+		if first.RawValue != "" {
+			continue
+		}
+		for _, second := range doc.Entries[i+1:] {
+			if second.Document != first.Document || second.Parent != first.Parent ||
+				second.Key != first.Key || second.RawValue != "" {
+				continue
+			}
+			return first, second
+		}
+	}
+	return nil, nil
+}
+
+// mergeYAMLEntries moves the subtree of the second entry, with its comments,
+// to the end of the first entry's subtree and removes the second key
+func mergeYAMLEntries(doc *utils.YAMLDocument, first, second *utils.YAMLEntry) {
+	unit := doc.IndentUnit()
+
+	targetIndent := first.Indent + unit
+	if len(first.Children) > 0 {
+		targetIndent = first.Children[0].Indent
+	}
+	sourceIndent := second.Indent + unit
+	if len(second.Children) > 0 {
+		sourceIndent = second.Children[0].Indent
+	}
+	delta := targetIndent - sourceIndent
+
+	// Comments attached to the duplicate key move along with its children
+	commentStart := doc.LeadingCommentStart(second)
+	var moved []string
+	for _, comment := range doc.Lines[commentStart:second.Line] {
+		trimmed := strings.TrimSpace(comment)
+		moved = append(moved, strings.Repeat(" ", targetIndent)+trimmed)
+	}
+	moved = append(moved, reindentLines(doc.Lines[second.Line+1:second.End], delta)...)
+
+	insertAt := first.End
+	start, end := expandRemoval(doc.Lines, commentStart, second.End)
+	doc.RemoveLines(start, end)
+	doc.InsertLines(insertAt, moved...)
+}
@@ -0,0 +1,44 @@
+package recipes
+
+import "testing"
+
+func TestExpandProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "dotted key is expanded and merged",
+			content:  "spring.application.name: app\nspring:\n  profiles:\n    active: dev\n",
+			expected: "spring:\n  application:\n    name: app\n  profiles:\n    active: dev\n",
+		},
+		{
+			name:     "root-level logger category is kept",
+			content:  "logging.level.com.example: DEBUG\nserver.port: 8080\n",
+			expected: "logging.level.com.example: DEBUG\nserver:\n  port: 8080\n",
+		},
+		{
+			name:     "nested logger category is kept",
+			content:  "logging:\n  level:\n    com.example: DEBUG\n",
+			expected: "logging:\n  level:\n    com.example: DEBUG\n",
+		},
+		{
+			name:     "map-valued property itself is expanded",
+			content:  "logging.level:\n  com.example: DEBUG\n",
+			expected: "logging:\n  level:\n    com.example: DEBUG\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, warnings := runRecipe(t, NewExpandPropertiesRecipe(""), "src/main/resources/application.yml", tt.content)
+			if actual != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", actual, tt.expected)
+			}
+			if len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %v", warnings)
+			}
+		})
+	}
+}
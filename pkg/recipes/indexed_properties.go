@@ -0,0 +1,153 @@
+package recipes
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// propertiesKeys returns the keys of a properties document in file order
+func propertiesKeys(doc *utils.PropertiesDocument) []string {
+	keys := make([]string, len(doc.Entries))
+	for i, entry := range doc.Entries {
+		keys[i] = entry.Key
+	}
+	return keys
+}
+
+// listIndexes returns the indexes each indexed list of the keys uses, by the
+// part of the key before the index, e.g. 0 and 1 for "a.list" of "a.list[0]"
+// and "a.list[1].name"
+func listIndexes(keys []string) map[string]map[int]bool {
+	lists := make(map[string]map[int]bool)
+	for _, key := range keys {
+		for _, index := range findKeyIndexes(key) {
+			list := key[:index.start]
+			if lists[list] == nil {
+				lists[list] = make(map[int]bool)
+			}
+			lists[list][index.value] = true
+		}
+	}
+	return lists
+}
+
+// keyIndex is a list index "[n]" in a property key
+type keyIndex struct {
+	start, end int // offsets of the brackets
+	value      int
+}
+
+// findKeyIndexes returns the list indexes of a key
+func findKeyIndexes(key string) []keyIndex {
+	var indexes []keyIndex
+	for start := strings.IndexByte(key, '['); start >= 0; {
+		closing := strings.IndexByte(key[start:], ']')
+		if closing < 0 {
+			break
+		}
+		if value, err := strconv.Atoi(key[start+1 : start+closing]); err == nil && value >= 0 {
+			indexes = append(indexes, keyIndex{start: start, end: start + closing + 1, value: value})
+		}
+		next := strings.IndexByte(key[start+closing:], '[')
+		if next < 0 {
+			break
+		}
+		start += closing + next
+	}
+	return indexes
+}
+
+// renumberPropertyLists closes the gaps that removing properties left in
+// indexed lists, since Spring stops binding a list at the first missing index.
+// Only lists that lost an index are renumbered; keys holds the keys before the removal.
+func renumberPropertyLists(doc *utils.PropertiesDocument, keys []string) {
+	before, after := listIndexes(keys), listIndexes(propertiesKeys(doc))
+
+	renumbered := make(map[string]map[int]int)
+	for list, indexes := range after {
+		if len(indexes) == len(before[list]) {
+			continue
+		}
+		remaining := make([]int, 0, len(indexes))
+		for index := range indexes {
+			remaining = append(remaining, index)
+		}
+		sort.Ints(remaining)
+		renumbered[list] = make(map[int]int)
+		for i, index := range remaining {
+			renumbered[list][index] = i
+		}
+	}
+	if len(renumbered) == 0 {
+		return
+	}
+
+	for i := 0; i < len(doc.Entries); i++ {
+		entry := doc.Entries[i]
+		// Lists are found by the key as written, so outer indexes are replaced last
+		key := entry.Key
+		indexes := findKeyIndexes(key)
+		for j := len(indexes) - 1; j >= 0; j-- {
+			index := indexes[j]
+			if newIndex, ok := renumbered[entry.Key[:index.start]][index.value]; ok && newIndex != index.value {
+				key = key[:index.start] + "[" + strconv.Itoa(newIndex) + "]" + key[index.end:]
+			}
+		}
+		if key != entry.Key {
+			doc.SetKey(entry, key)
+		}
+	}
+}
+
+// zeroListIndexes returns key with every list index replaced by 0
+func zeroListIndexes(key string) string {
+	indexes := findKeyIndexes(key)
+	for i := len(indexes) - 1; i >= 0; i-- {
+		key = key[:indexes[i].start] + "[0]" + key[indexes[i].end:]
+	}
+	return key
+}
+
+// copyListIndexes returns path with its list indexes replaced, in order, by
+// those of key
+func copyListIndexes(path, key string) string {
+	indexes, replacements := findKeyIndexes(path), findKeyIndexes(key)
+	for i := len(indexes) - 1; i >= 0; i-- {
+		if i < len(replacements) {
+			path = path[:indexes[i].start] + "[" + strconv.Itoa(replacements[i].value) + "]" + path[indexes[i].end:]
+		}
+	}
+	return path
+}
+
+// flowItemKey returns the key of an item of the flow sequence of an entry
+func flowItemKey(entry *utils.YAMLEntry, index int) string {
+	return entry.Path + "[" + strconv.Itoa(index) + "]"
+}
+
+// uneditableFlowKeys returns the matching keys that are set inside flow
+// collections other than flow sequences of scalars, which are left unchanged
+func uneditableFlowKeys(doc *utils.YAMLDocument, match func(key string) bool) []string {
+	var keys []string
+	for _, entry := range doc.Entries {
+		if !entry.HasValue() || !strings.ContainsAny(entry.RawValue[:1], "[{") || match(entry.Path) {
+			continue
+		}
+		if _, ok := entry.FlowSequenceItems(); ok {
+			continue
+		}
+		properties, err := doc.FlattenDocument(entry.Document)
+		if err != nil {
+			continue
+		}
+		for _, property := range properties {
+			if property.Line == entry.Line && property.Key != entry.Path && match(property.Key) {
+				keys = append(keys, property.Key)
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,130 @@
+package recipes
+
+import (
+	"strings"
+	"testing"
+)
+
+const flowSequenceYAML = `management:
+  endpoints:
+    web:
+      exposure:
+        include: [health, info]  # exposed
+`
+
+func TestDeleteFlowSequenceItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		content  string
+		expected string
+	}{
+		{
+			name:     "last item",
+			key:      "management.endpoints.web.exposure.include[1]",
+			content:  flowSequenceYAML,
+			expected: strings.Replace(flowSequenceYAML, "[health, info]", "[health]", 1),
+		},
+		{
+			name:     "first item renumbers the rest",
+			key:      "management.endpoints.web.exposure.include[0]",
+			content:  flowSequenceYAML,
+			expected: strings.Replace(flowSequenceYAML, "[health, info]", "[info]", 1),
+		},
+		{
+			name:     "every item removes the sequence",
+			key:      "management.endpoints.web.exposure.include[*]",
+			content:  flowSequenceYAML + "server:\n  port: 8080\n",
+			expected: "server:\n  port: 8080\n",
+		},
+		{
+			name:     "spacing is kept",
+			key:      "a.list[1]",
+			content:  "a:\n  list: [ 'x' ,'y' ,z ]\n",
+			expected: "a:\n  list: [ 'x' ,z ]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, warnings := runRecipe(t, NewDeleteSpringPropertyRecipe(tt.key), "src/main/resources/application.yml", tt.content)
+			if actual != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", actual, tt.expected)
+			}
+			if len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %v", warnings)
+			}
+		})
+	}
+}
+
+func TestChangeFlowSequenceItemValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		expected string
+	}{
+		{
+			name:     "plain item",
+			key:      "management.endpoints.web.exposure.include[1]",
+			value:    "metrics",
+			expected: strings.Replace(flowSequenceYAML, "[health, info]", "[health, metrics]", 1),
+		},
+		{
+			name:     "value needing quotes",
+			key:      "management.endpoints.web.exposure.include[0]",
+			value:    "a,b",
+			expected: strings.Replace(flowSequenceYAML, "[health, info]", `["a,b", info]`, 1),
+		},
+		{
+			name:     "every item",
+			key:      "management.endpoints.web.exposure.include[*]",
+			value:    "*",
+			expected: strings.Replace(flowSequenceYAML, "[health, info]", `["*", "*"]`, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := NewChangeSpringPropertyValueRecipe(tt.key, tt.value, "", false)
+			actual, _ := runRecipe(t, recipe, "src/main/resources/application.yml", flowSequenceYAML)
+			if actual != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddFlowSequenceItem(t *testing.T) {
+	recipe := NewAddSpringPropertyRecipe("management.endpoints.web.exposure.include[2]", "metrics", "", nil)
+	actual, warnings := runRecipe(t, recipe, "src/main/resources/application.yml", flowSequenceYAML)
+	expected := strings.Replace(flowSequenceYAML, "[health, info]", "[health, info, metrics]", 1)
+	if actual != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", actual, expected)
+	}
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	recipe = NewAddSpringPropertyRecipe("management.endpoints.web.exposure.include[3]", "metrics", "", nil)
+	actual, warnings = runRecipe(t, recipe, "src/main/resources/application.yml", flowSequenceYAML)
+	if actual != flowSequenceYAML || len(warnings) != 1 {
+		t.Errorf("expected a gap to be refused with a warning, got:\n%s\nwarnings: %v", actual, warnings)
+	}
+}
+
+func TestFlowMappingEditsAreReported(t *testing.T) {
+	content := "x:\n  m: {a: 1, b: [2, 3]}\n"
+
+	actual, warnings := runRecipe(t, NewDeleteSpringPropertyRecipe("x.m.b[0]"), "src/main/resources/application.yml", content)
+	if actual != content || len(warnings) != 1 {
+		t.Errorf("delete: got:\n%s\nwarnings: %v", actual, warnings)
+	}
+
+	recipe := NewChangeSpringPropertyValueRecipe("x.m.a", "5", "", false)
+	actual, warnings = runRecipe(t, recipe, "src/main/resources/application.yml", content)
+	if actual != content || len(warnings) != 1 {
+		t.Errorf("change value: got:\n%s\nwarnings: %v", actual, warnings)
+	}
+}
@@ -0,0 +1,112 @@
+package recipes
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// bootstrapFilePattern matches bootstrap files and captures their profile suffix
+var bootstrapFilePattern = regexp.MustCompile(`^bootstrap(-[^.]+)?\.(properties|yml|yaml)$`)
+
+// MergeBootstrapYamlWithApplicationYamlRecipe merges bootstrap.yml and
+// bootstrap.properties into the sibling application file and deletes them
+type MergeBootstrapYamlWithApplicationYamlRecipe struct {
+	core.BaseRecipe
+}
+
+// NewMergeBootstrapYamlWithApplicationYamlRecipe creates a new MergeBootstrapYamlWithApplicationYaml recipe
+func NewMergeBootstrapYamlWithApplicationYamlRecipe() *MergeBootstrapYamlWithApplicationYamlRecipe {
+	return &MergeBootstrapYamlWithApplicationYamlRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Merge bootstrap configuration into application configuration",
+			Description: "In Spring Boot 2.4, support for `bootstrap.yml` was removed unless the legacy bootstrap is enabled. " +
+				"Merges `bootstrap.yml` and `bootstrap.properties` into the sibling `application` file, document by document " +
+				"and converting between the formats when the application file has the other one, " +
+				"keeping the application values when both define a property.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
+		},
+	}
+}
+
+// Apply cannot delete the bootstrap file on its own, so the file is returned
+// unchanged; the runner uses ApplyAll instead
+func (r *MergeBootstrapYamlWithApplicationYamlRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	return sourceFile, nil
+}
+
+// ApplyAll merges every bootstrap file in the set and returns the resulting files
+func (r *MergeBootstrapYamlWithApplicationYamlRecipe) ApplyAll(ctx context.Context, sourceFiles []core.SourceFile) ([]core.SourceFile, error) {
+	logger := core.GetExecutionContext(ctx).Logger
+	files := newSourceFileSet(sourceFiles)
+
+	for _, sourceFile := range sourceFiles {
+		match := bootstrapFilePattern.FindStringSubmatch(strings.ToLower(filepath.Base(sourceFile.GetPath())))
+		kind := sourceFile.GetConfigKind()
+		if match == nil || (kind != core.SpringApplicationConfig && kind != core.SpringProfileConfig) {
+			continue
+		}
+
+		applicationPath := applicationFilePath(files, sourceFile, match[1])
+		content := sourceFile.GetContent()
+		targetType := utils.DetermineFileType(applicationPath)
+		if targetType != sourceFile.GetType() {
+			// Merge into the existing application file of the other format
+			var err error
+			if targetType == core.YAML {
+				content, err = utils.ConvertPropertiesToYAML(content)
+			} else {
+				content, err = utils.ConvertYAMLToProperties(content)
+			}
+			if err != nil {
+				logger.Warn("%s cannot be merged into %s: %v", sourceFile.GetPath(), applicationPath, err)
+				continue
+			}
+		}
+		applicationFile := files.getOrCreate(applicationPath, sourceFile.GetEncoding())
+
+		var conflicts []mergeConflict
+		switch targetType {
+		case core.Properties:
+			var merged string
+			merged, conflicts = mergePropertiesDocuments(applicationFile.GetContent(), content)
+			applicationFile.SetContent(merged)
+		case core.YAML:
+			target := utils.ParseYAML(applicationFile.GetContent())
+			conflicts = mergeYAMLDocuments(target, utils.ParseYAML(content))
+			applicationFile.SetContent(ensureTrailingNewline(target.String()))
+		}
+
+		for _, conflict := range conflicts {
+			logger.Warn("%s already sets %s to %q; ignoring %q from %s",
+				applicationPath, conflict.Path, conflict.ExistingValue, conflict.IgnoredValue, sourceFile.GetPath())
+		}
+
+		files.remove(sourceFile.GetPath())
+	}
+
+	return files.list(), nil
+}
+
+// applicationFilePath returns the application file next to a bootstrap file,
+// preferring an existing file of the same format over one of the other format
+func applicationFilePath(files *sourceFileSet, bootstrapFile core.SourceFile, profileSuffix string) string {
+	dir := filepath.Dir(bootstrapFile.GetPath())
+	ext := filepath.Ext(bootstrapFile.GetPath())
+
+	extensions := []string{ext, ".yml", ".yaml"}
+	if bootstrapFile.GetType() == core.YAML {
+		extensions = append(extensions, ".properties")
+	}
+	for _, candidate := range extensions {
+		path := filepath.Join(dir, "application"+profileSuffix+candidate)
+		if files.get(path) != nil || utils.FileExists(path) {
+			return path
+		}
+	}
+	return filepath.Join(dir, "application"+profileSuffix+ext)
+}
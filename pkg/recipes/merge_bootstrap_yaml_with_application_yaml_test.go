@@ -0,0 +1,94 @@
+package recipes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+func TestMergeBootstrapYamlWithApplicationYaml(t *testing.T) {
+	const resources = "src/main/resources/"
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected map[string]string
+		warnings []string
+	}{
+		{
+			name: "properties documents",
+			files: map[string]string{
+				resources + "bootstrap.properties":   "spring.cloud.config.uri=http://config\n#---\nspring.config.activate.on-profile=dev\nspring.cloud.config.uri=http://dev\n",
+				resources + "application.properties": "server.port=8080\n#---\nspring.config.activate.on-profile=dev\nserver.port=8081\n",
+			},
+			expected: map[string]string{
+				resources + "application.properties": "server.port=8080\n\nspring.cloud.config.uri=http://config\n#---\nspring.config.activate.on-profile=dev\nserver.port=8081\n\nspring.cloud.config.uri=http://dev\n",
+			},
+		},
+		{
+			name: "properties into an existing YAML file",
+			files: map[string]string{
+				resources + "bootstrap.properties": "spring.application.name=app\nspring.cloud.config.uri=http://config\n",
+				resources + "application.yml":      "spring:\n  application:\n    name: service\nserver:\n  port: 8080\n",
+			},
+			expected: map[string]string{
+				resources + "application.yml": "spring:\n  application:\n    name: service\n  cloud:\n    config:\n      uri: http://config\nserver:\n  port: 8080\n",
+			},
+			warnings: []string{
+				resources + `application.yml already sets spring.application.name to "service"; ignoring "app" from ` + resources + "bootstrap.properties",
+			},
+		},
+		{
+			name: "YAML into an existing properties file",
+			files: map[string]string{
+				resources + "bootstrap.yml":          "spring:\n  cloud:\n    config:\n      uri: http://config\n",
+				resources + "application.properties": "server.port=8080\n",
+			},
+			expected: map[string]string{
+				resources + "application.properties": "server.port=8080\n\nspring.cloud.config.uri=http://config\n",
+			},
+		},
+		{
+			name: "properties YAML cannot represent",
+			files: map[string]string{
+				resources + "bootstrap.properties": "a=1\na.b=2\n",
+				resources + "application.yml":      "server:\n  port: 8080\n",
+			},
+			expected: map[string]string{
+				resources + "bootstrap.properties": "a=1\na.b=2\n",
+				resources + "application.yml":      "server:\n  port: 8080\n",
+			},
+			warnings: []string{
+				resources + "bootstrap.properties cannot be merged into " + resources + "application.yml: a has both a value and nested properties",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sourceFiles []core.SourceFile
+			for path, content := range tt.files {
+				sourceFiles = append(sourceFiles, utils.NewSourceFile(path, content, core.DefaultEncoding))
+			}
+			logger := &recordingLogger{}
+			ctx := core.WithExecutionContext(context.Background(), &core.ExecutionContext{Logger: logger})
+			results, err := NewMergeBootstrapYamlWithApplicationYamlRecipe().ApplyAll(ctx, sourceFiles)
+			if err != nil {
+				t.Fatalf("ApplyAll() error = %v", err)
+			}
+
+			actual := make(map[string]string)
+			for _, result := range results {
+				actual[result.GetPath()] = result.GetContent()
+			}
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("ApplyAll() = %q, want %q", actual, tt.expected)
+			}
+			if !reflect.DeepEqual(logger.warnings, tt.warnings) {
+				t.Errorf("warnings = %q, want %q", logger.warnings, tt.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package recipes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+const (
+	deprecationCommentPrefix  = "This property is deprecated: "
+	defaultDeprecationComment = "This property is deprecated and will be removed in future Spring Boot versions"
+	removedPropertyComment    = "This property is no longer supported"
+)
+
+// MigrationRecipeGenerator writes a declarative recipe that migrates the
+// deprecated properties of configuration metadata, in the format of the
+// spring-boot-*-properties.yml catalog: properties with a replacement are
+// renamed, and removed properties, those deprecated at level "error", are
+// commented out.
+type MigrationRecipeGenerator struct {
+	// Name is the fully qualified name of the generated recipe
+	Name        string
+	DisplayName string
+	Description string
+	// CommentOutDeprecated also comments out the properties without a replacement
+	// that are deprecated at level "warning", which are still bound
+	CommentOutDeprecated bool
+}
+
+// NewMigrationRecipeGenerator creates a generator for a recipe of the given name
+func NewMigrationRecipeGenerator(name string) *MigrationRecipeGenerator {
+	return &MigrationRecipeGenerator{
+		Name:        name,
+		DisplayName: "Migrate deprecated Spring properties",
+		Description: "Migrate properties found in `application.properties` and `application.yml`.",
+	}
+}
+
+// Deprecations returns the deprecated properties of the metadata the recipe
+// migrates, sorted by name, keeping one property per name and replacement
+func (g *MigrationRecipeGenerator) Deprecations(metadata *utils.ConfigurationMetadata) []*utils.ConfigurationProperty {
+	seen := make(map[string]bool)
+	var deprecations []*utils.ConfigurationProperty
+	for _, property := range metadata.Properties {
+		if !property.IsDeprecated() {
+			continue
+		}
+		key := property.Name
+		if replacement := deprecationReplacement(property); replacement != "" {
+			key += "->" + replacement
+		} else if !isRemovedProperty(property) && !g.CommentOutDeprecated {
+			continue
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deprecations = append(deprecations, property)
+	}
+	sort.SliceStable(deprecations, func(i, j int) bool {
+		return deprecations[i].Name < deprecations[j].Name
+	})
+	return deprecations
+}
+
+// Generate returns the recipe YAML for the deprecated properties of the metadata
+func (g *MigrationRecipeGenerator) Generate(metadata *utils.ConfigurationMetadata) string {
+	deprecations := g.Deprecations(metadata)
+
+	var b strings.Builder
+	b.WriteString("# This file is automatically generated by rewrite-spring-go generate-recipes.\n")
+	b.WriteString("# Do not edit this file manually. Update the configuration metadata instead.\n")
+	b.WriteString("---\n")
+	b.WriteString("type: specs.openrewrite.org/v1beta/recipe\n")
+	b.WriteString("name: " + yamlScalar(g.Name) + "\n")
+	b.WriteString("displayName: " + yamlScalar(g.DisplayName) + "\n")
+	b.WriteString("description: " + yamlScalar(g.Description) + "\n")
+	b.WriteString("tags:\n  - spring\n  - boot\n")
+	if len(deprecations) == 0 {
+		b.WriteString("recipeList: []\n")
+		return b.String()
+	}
+	b.WriteString("recipeList:\n")
+
+	for _, property := range deprecations {
+		if replacement := deprecationReplacement(property); replacement != "" {
+			b.WriteString("  - org.openrewrite.java.spring.ChangeSpringPropertyKey:\n")
+			b.WriteString("      oldPropertyKey: " + yamlScalar(property.Name) + "\n")
+			b.WriteString("      newPropertyKey: " + yamlScalar(replacement) + "\n")
+		}
+	}
+	for _, property := range deprecations {
+		if deprecationReplacement(property) == "" {
+			b.WriteString("  - org.openrewrite.java.spring.CommentOutSpringPropertyKey:\n")
+			b.WriteString("      propertyKey: " + yamlScalar(property.Name) + "\n")
+			b.WriteString("      comment: " + utils.QuoteYAMLScalar(deprecationComment(property), '"') + "\n")
+		}
+	}
+	return b.String()
+}
+
+// deprecationReplacement returns the key that replaces a deprecated property, if any
+func deprecationReplacement(property *utils.ConfigurationProperty) string {
+	if property.Deprecation == nil {
+		return ""
+	}
+	return strings.TrimSpace(property.Deprecation.Replacement)
+}
+
+// isRemovedProperty reports whether a deprecated property is no longer supported
+func isRemovedProperty(property *utils.ConfigurationProperty) bool {
+	return property.Deprecation != nil && property.Deprecation.Level == "error"
+}
+
+// deprecationComment returns the comment left above a property without a replacement
+func deprecationComment(property *utils.ConfigurationProperty) string {
+	if property.Deprecation == nil {
+		return defaultDeprecationComment
+	}
+	if reason := strings.TrimSpace(property.Deprecation.Reason); reason != "" {
+		return deprecationCommentPrefix + reason
+	}
+	if property.Deprecation.Level == "error" {
+		return removedPropertyComment
+	}
+	return defaultDeprecationComment
+}
+
+// yamlScalar returns a value as a plain YAML scalar, or double-quoted when it has to be
+func yamlScalar(value string) string {
+	if utils.NeedsYAMLQuotes(value) {
+		return utils.QuoteYAMLScalar(value, '"')
+	}
+	return value
+}
@@ -0,0 +1,62 @@
+package recipes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+func TestMigrationRecipeGeneratorDeprecationLevels(t *testing.T) {
+	metadata := &utils.ConfigurationMetadata{Properties: []*utils.ConfigurationProperty{
+		{Name: "acme.renamed", Deprecation: &utils.Deprecation{Level: "warning", Replacement: "acme.new-name"}},
+		{Name: "acme.removed", Deprecation: &utils.Deprecation{Level: "error"}},
+		{Name: "acme.still-bound", Deprecation: &utils.Deprecation{Level: "warning", Reason: "Use a bean instead"}},
+		{Name: "acme.legacy", Deprecated: true},
+		{Name: "acme.current"},
+	}}
+
+	tests := []struct {
+		name                 string
+		commentOutDeprecated bool
+		expected             []string
+		unexpected           []string
+	}{
+		{
+			name: "removed properties only",
+			expected: []string{
+				"oldPropertyKey: acme.renamed\n      newPropertyKey: acme.new-name\n",
+				"propertyKey: acme.removed\n      comment: \"This property is no longer supported\"\n",
+			},
+			unexpected: []string{"acme.still-bound", "acme.legacy", "acme.current"},
+		},
+		{
+			name:                 "warning-level deprecations opted in",
+			commentOutDeprecated: true,
+			expected: []string{
+				"propertyKey: acme.removed\n",
+				"propertyKey: acme.still-bound\n      comment: \"This property is deprecated: Use a bean instead\"\n",
+				"propertyKey: acme.legacy\n",
+			},
+			unexpected: []string{"acme.current"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := NewMigrationRecipeGenerator("com.example.Migrate")
+			generator.CommentOutDeprecated = tt.commentOutDeprecated
+			recipe := generator.Generate(metadata)
+			for _, expected := range tt.expected {
+				if !strings.Contains(recipe, expected) {
+					t.Errorf("recipe is missing %q:\n%s", expected, recipe)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(recipe, unexpected) {
+					t.Errorf("recipe should not mention %s:\n%s", unexpected, recipe)
+				}
+			}
+		})
+	}
+}
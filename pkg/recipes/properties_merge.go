@@ -0,0 +1,92 @@
+package recipes
+
+import (
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// mergePropertiesInto appends the properties of content to target together with
+// their comments. Properties already defined in target keep their value; the
+// conflicting definitions are dropped and returned.
+func mergePropertiesInto(target *utils.PropertiesDocument, content string) []mergeConflict {
+	addition := utils.ParseProperties(strings.Trim(content, "\n"))
+
+	var conflicts []mergeConflict
+	var lines []string
+	for _, added := range addition.Entries {
+		if existing := target.Find(added.Key); existing != nil {
+			if existing.Value != added.Value {
+				conflicts = append(conflicts, mergeConflict{
+					Path:          added.Key,
+					ExistingValue: existing.Value,
+					IgnoredValue:  added.Value,
+				})
+			}
+			continue
+		}
+		lines = append(lines, addition.Lines[addition.LeadingCommentStart(added):added.End]...)
+	}
+
+	if len(lines) == 0 {
+		return conflicts
+	}
+
+	// Append after the last content of the target, separated by a blank line
+	end := len(target.Lines)
+	for end > 0 && strings.TrimSpace(target.Lines[end-1]) == "" {
+		end--
+	}
+	if end > 0 {
+		lines = append([]string{""}, lines...)
+	}
+	target.RemoveLines(end, len(target.Lines))
+	target.InsertLines(end, append(lines, "")...)
+	return conflicts
+}
+
+// mergePropertiesDocuments merges each document of addition into the target
+// document activated for the same profiles, appending documents that have no
+// counterpart, and returns the merged content
+func mergePropertiesDocuments(target, addition string) (string, []mergeConflict) {
+	var documents []*utils.PropertiesDocument
+	for _, document := range utils.SplitPropertiesDocuments(strings.TrimRight(target, "\n")) {
+		documents = append(documents, utils.ParseProperties(document))
+	}
+
+	var conflicts []mergeConflict
+	for _, content := range utils.SplitPropertiesDocuments(addition) {
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+		activation := propertiesActivation(utils.ParseProperties(content))
+		merged := false
+		for _, document := range documents {
+			if propertiesActivation(document) == activation {
+				conflicts = append(conflicts, mergePropertiesInto(document, content)...)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			documents = append(documents, utils.ParseProperties(strings.Trim(content, "\n")))
+		}
+	}
+
+	contents := make([]string, len(documents))
+	for i, document := range documents {
+		contents[i] = strings.Trim(document.String(), "\n")
+	}
+	return strings.Join(contents, "\n#---\n") + "\n", conflicts
+}
+
+// propertiesActivation returns the profile expression a properties document is
+// activated for, or "" for a document that always applies
+func propertiesActivation(doc *utils.PropertiesDocument) string {
+	for _, key := range ProfileActivationKeys {
+		if entry := doc.Find(key); entry != nil {
+			return utils.UnescapePropertiesValue(entry.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package recipes
+
+import (
+	"context"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// PropertiesToKebabCaseRecipe normalizes Spring property keys to kebab-case
+type PropertiesToKebabCaseRecipe struct {
+	core.BaseRecipe
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
+}
+
+// NewPropertiesToKebabCaseRecipe creates a new PropertiesToKebabCase recipe
+func NewPropertiesToKebabCaseRecipe() *PropertiesToKebabCaseRecipe {
+	return &PropertiesToKebabCaseRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Normalize Spring properties to kebab-case",
+			Description: "Normalize Spring properties to use lowercase and hyphen-separated syntax. " +
+				"For example, changing `spring.main.showBanner` to `spring.main.show-banner`. " +
+				"Keys of map-valued properties such as `logging.level` are user-defined names and are left untouched.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
+		},
+	}
+}
+
+// Apply executes the recipe on the provided source file
+func (r *PropertiesToKebabCaseRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+
+	switch sourceFile.GetType() {
+	case core.Properties:
+		return r.applyToProperties(sourceFile)
+	case core.YAML:
+		return r.applyToYAML(sourceFile)
+	default:
+		return sourceFile, nil
+	}
+}
+
+// applyToProperties rewrites every key of a properties file
+func (r *PropertiesToKebabCaseRecipe) applyToProperties(sourceFile core.SourceFile) (core.SourceFile, error) {
+	doc := utils.ParseProperties(sourceFile.GetContent())
+	modified := false
+
+	for i := 0; i < len(doc.Entries); i++ {
+		entry := doc.Entries[i]
+		if kebabKey := utils.KebabCaseKey("", entry.Key); kebabKey != entry.Key {
+			doc.SetKey(entry, kebabKey)
+			modified = true
+		}
+	}
+
+	if modified {
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
+
+// applyToYAML rewrites every mapping key of a YAML file, resolving map-valued
+// properties against the canonical path of the enclosing mappings
+func (r *PropertiesToKebabCaseRecipe) applyToYAML(sourceFile core.SourceFile) (core.SourceFile, error) {
+	doc := utils.ParseYAML(sourceFile.GetContent())
+	modified := false
+
+	for i := 0; i < len(doc.Entries); i++ {
+		entry := doc.Entries[i]
+		parentPath := ""
+		if entry.Parent != nil {
+			parentPath = utils.KebabCaseKey("", entry.Parent.Path)
+		}
+		if kebabKey := utils.KebabCaseKey(parentPath, entry.Key); kebabKey != entry.Key {
+			doc.SetKey(entry, kebabKey)
+			modified = true
+		}
+	}
+
+	if modified {
+		sourceFile.SetContent(doc.String())
+	}
+
+	return sourceFile, nil
+}
@@ -0,0 +1,33 @@
+package recipes
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// recordingLogger keeps the warnings recipes log
+type recordingLogger struct {
+	core.NullLogger
+	warnings []string
+}
+
+func (l *recordingLogger) Warn(msg string, args ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(msg, args...))
+}
+
+// runRecipe applies a recipe to a file with the given content and returns the
+// resulting content and the warnings logged
+func runRecipe(t *testing.T, recipe core.Recipe, path, content string) (string, []string) {
+	t.Helper()
+	logger := &recordingLogger{}
+	ctx := core.WithExecutionContext(context.Background(), &core.ExecutionContext{Logger: logger})
+	result, err := recipe.Apply(ctx, utils.NewSourceFile(path, content, core.DefaultEncoding))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	return result.GetContent(), logger.warnings
+}
@@ -0,0 +1,301 @@
+package recipes
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// FindSecretsRecipe reports property values and placeholder defaults of Spring
+// configuration files that likely hold plaintext secrets
+type FindSecretsRecipe struct {
+	core.BaseRecipe
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
+}
+
+// NewFindSecretsRecipe creates a new FindSecrets recipe
+func NewFindSecretsRecipe() *FindSecretsRecipe {
+	return &FindSecretsRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Find plaintext secrets in Spring configuration",
+			Description: "Report property values and `${...}` placeholder defaults that likely hold secrets, " +
+				"either because their key is named like a password, secret, token or key, or because they look " +
+				"like a generated key.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
+		},
+	}
+}
+
+// Apply executes the recipe on the provided source file
+func (r *FindSecretsRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+
+	executionContext := core.GetExecutionContext(ctx)
+	rewriteScalarValues(sourceFile, func(key, value string, line int) (string, bool) {
+		for _, secret := range findSecretValues(key, value) {
+			message := fmt.Sprintf("Possible secret in '%s'", key)
+			if secret.placeholderKey != "" {
+				message = fmt.Sprintf("Possible secret in the default of ${%s} in '%s'", secret.placeholderKey, key)
+			}
+			executionContext.Report(sourceFile, line, core.Finding{Key: key, Severity: core.Error, Message: message})
+		}
+		return value, false
+	})
+
+	return sourceFile, nil
+}
+
+// ExternalizeSecretsRecipe replaces the likely secrets of Spring configuration
+// files with placeholders of environment variables, and writes the values to
+// a .env file that is added to the .gitignore next to it
+type ExternalizeSecretsRecipe struct {
+	core.BaseRecipe
+	// EnvFile is the path of the .env file receiving the secrets
+	EnvFile string
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
+}
+
+// NewExternalizeSecretsRecipe creates a new ExternalizeSecrets recipe writing to envFile
+func NewExternalizeSecretsRecipe(envFile string) *ExternalizeSecretsRecipe {
+	return &ExternalizeSecretsRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Externalize plaintext secrets of Spring configuration",
+			Description: "Replace property values and `${...}` placeholder defaults that likely hold secrets with " +
+				"`${ENV_NAME}` placeholders, and move the values to a .env file that is kept out of version control.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
+		},
+		EnvFile: envFile,
+	}
+}
+
+// Apply cannot create the .env file on its own, so the file is returned
+// unchanged; the runner uses ApplyAll instead
+func (r *ExternalizeSecretsRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	return sourceFile, nil
+}
+
+// ApplyAll moves the secrets of every file into the .env file
+func (r *ExternalizeSecretsRecipe) ApplyAll(ctx context.Context, sourceFiles []core.SourceFile) ([]core.SourceFile, error) {
+	logger := core.GetExecutionContext(ctx).Logger
+	files := newSourceFileSet(sourceFiles)
+	envFile := files.getOrCreate(r.EnvFile, core.DefaultEncoding)
+
+	defined := make(map[string]bool)
+	for _, variable := range utils.FindEnvironmentVariables(strings.Split(envFile.GetContent(), "\n"), core.DotEnv) {
+		defined[variable.Name] = true
+	}
+	values := make(map[string]string)
+	var added []string
+
+	// assign returns the variable a secret moves to. A placeholder keeps its key,
+	// so that deployments setting the variable still resolve it; a literal gets
+	// a numbered name when the variable already holds another secret of this run.
+	assign := func(sourceFile core.SourceFile, line int, secret secretValue, value string) string {
+		name := secret.envName
+		if secret.placeholderKey != "" {
+			if existing, ok := values[name]; ok {
+				if existing != value {
+					logger.Warn("%s:%d: the default of ${%s} differs from the value already moved to %s; keeping the first",
+						sourceFile.GetPath(), line+1, secret.placeholderKey, name)
+				}
+				return name
+			}
+		} else {
+			for n := 2; ; n++ {
+				existing, ok := values[name]
+				if !ok {
+					break
+				}
+				if existing == value {
+					return name
+				}
+				name = fmt.Sprintf("%s_%d", secret.envName, n)
+			}
+		}
+		if defined[name] {
+			logger.Warn("%s:%d: %s is already defined in %s; keeping its value",
+				sourceFile.GetPath(), line+1, name, r.EnvFile)
+		} else {
+			values[name] = value
+			added = append(added, name)
+		}
+		return name
+	}
+
+	// Secrets of the default configuration keep the plain names; profiles come after
+	ordered := make([]core.SourceFile, 0, len(sourceFiles))
+	for _, sourceFile := range sourceFiles {
+		if sourceFile.GetConfigKind() != core.SpringProfileConfig {
+			ordered = append(ordered, sourceFile)
+		}
+	}
+	for _, sourceFile := range sourceFiles {
+		if sourceFile.GetConfigKind() == core.SpringProfileConfig {
+			ordered = append(ordered, sourceFile)
+		}
+	}
+
+	for _, sourceFile := range ordered {
+		if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+			continue
+		}
+		rewriteScalarValues(sourceFile, func(key, value string, line int) (string, bool) {
+			secrets := findSecretValues(key, value)
+			// From the end, so that the offsets of earlier secrets stay valid
+			for i := len(secrets) - 1; i >= 0; i-- {
+				secret := secrets[i]
+				literal := secret.value
+				if sourceFile.GetType() == core.Properties {
+					literal = utils.UnescapePropertiesValue(literal)
+				}
+				name := assign(sourceFile, line, secret, literal)
+				value = value[:secret.start] + secret.replacement(name) + value[secret.end:]
+				logger.Info("%s:%d: moved the value of %s to %s", sourceFile.GetPath(), line+1, key, name)
+			}
+			return value, len(secrets) > 0
+		})
+	}
+
+	if len(added) > 0 {
+		content := envFile.GetContent()
+		if strings.TrimSpace(content) == "" {
+			content = "# Secrets moved out of the Spring configuration; do not commit this file\n"
+		}
+		content = ensureTrailingNewline(content)
+		for _, name := range added {
+			content += name + "=" + formatDotEnvValue(values[name]) + "\n"
+		}
+		envFile.SetContent(content)
+		r.ignoreEnvFile(files)
+	} else {
+		files.remove(r.EnvFile)
+	}
+
+	return files.list(), nil
+}
+
+// ignoreEnvFile adds the .env file to the .gitignore of its directory
+func (r *ExternalizeSecretsRecipe) ignoreEnvFile(files *sourceFileSet) {
+	name := filepath.Base(r.EnvFile)
+	gitignorePath := filepath.Join(filepath.Dir(r.EnvFile), ".gitignore")
+	gitignore := files.getOrCreate(gitignorePath, core.DefaultEncoding)
+	for _, line := range strings.Split(gitignore.GetContent(), "\n") {
+		if line = strings.TrimSpace(line); line == name || line == "/"+name {
+			files.remove(gitignorePath)
+			return
+		}
+	}
+	content := gitignore.GetContent()
+	if content != "" {
+		content = ensureTrailingNewline(content)
+	}
+	gitignore.SetContent(content + "/" + name + "\n")
+}
+
+// secretValue is a literal in a property value that likely holds a secret
+type secretValue struct {
+	value      string
+	start, end int    // offsets of the text the placeholder replaces in the property value
+	envName    string // environment variable derived for the secret
+	// placeholderKey is the key of the placeholder whose default the secret is, if any
+	placeholderKey string
+}
+
+// replacement returns the placeholder replacing the secret once it is stored in
+// the named variable; a placeholder only loses its default
+func (s secretValue) replacement(name string) string {
+	if s.placeholderKey != "" {
+		return "${" + s.placeholderKey + "}"
+	}
+	return "${" + name + "}"
+}
+
+// findSecretValues returns the likely secrets of a property value: the whole
+// value when it is a literal, otherwise the literal defaults of its placeholders
+func findSecretValues(key, value string) []secretValue {
+	placeholders := utils.ParsePlaceholders(value)
+	if len(placeholders) == 0 {
+		if strings.Contains(value, "#{") || !utils.IsLikelySecret(key, value) {
+			return nil
+		}
+		return []secretValue{{value: value, start: 0, end: len(value), envName: utils.ToEnvironmentVariableName(key)}}
+	}
+
+	var secrets []secretValue
+	for _, placeholder := range placeholders {
+		if !placeholder.HasDefault || strings.Contains(placeholder.Default, "${") || strings.Contains(placeholder.Default, "#{") {
+			continue
+		}
+		if !utils.IsLikelySecret(placeholder.Key, placeholder.Default) && !utils.IsLikelySecret(key, placeholder.Default) {
+			continue
+		}
+		secrets = append(secrets, secretValue{
+			value:          placeholder.Default,
+			start:          placeholder.Start,
+			end:            placeholder.End,
+			envName:        utils.ToEnvironmentVariableName(placeholder.Key),
+			placeholderKey: placeholder.Key,
+		})
+	}
+	return secrets
+}
+
+// rewriteScalarValues calls rewrite with the key, value and line index of every
+// scalar value of a Properties or YAML file, and sets the values it changes.
+// Properties values are passed as written; YAML values without quotes.
+func rewriteScalarValues(sourceFile core.SourceFile, rewrite func(key, value string, line int) (string, bool)) {
+	modified := false
+	switch sourceFile.GetType() {
+	case core.Properties:
+		doc := utils.ParseProperties(sourceFile.GetContent())
+		for i := 0; i < len(doc.Entries); i++ {
+			entry := doc.Entries[i]
+			if newValue, ok := rewrite(entry.Key, entry.Value, entry.Line); ok {
+				doc.SetValue(entry, newValue)
+				modified = true
+			}
+		}
+		if modified {
+			sourceFile.SetContent(doc.String())
+		}
+	case core.YAML:
+		doc := utils.ParseYAML(sourceFile.GetContent())
+		for i := 0; i < len(doc.Entries); i++ {
+			entry := doc.Entries[i]
+			// Flow collections, anchors, aliases and tags are not plain values
+			if !entry.HasValue() || strings.ContainsAny(entry.RawValue[:1], "[{&*!") {
+				continue
+			}
+			if newValue, ok := rewrite(entry.Path, entry.Value, entry.Line); ok {
+				doc.SetValue(entry, utils.FormatYAMLScalar(newValue, entry.RawValue))
+				modified = true
+			}
+		}
+		if modified {
+			sourceFile.SetContent(doc.String())
+		}
+	}
+}
+
+// formatDotEnvValue writes a value for a .env file, quoting it when it holds
+// whitespace, quotes, comments or expansions
+func formatDotEnvValue(value string) string {
+	if !strings.ContainsAny(value, " \t\n\"'#$\\`") {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\n") {
+		return "'" + value + "'"
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`).Replace(value)
+	return `"` + escaped + `"`
+}
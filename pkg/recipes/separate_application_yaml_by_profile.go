@@ -0,0 +1,143 @@
+package recipes
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// ProfileActivationKeys are the keys that gate a YAML document on a profile,
+// the Spring Boot 2.4+ key first and the legacy key second
+var ProfileActivationKeys = []string{
+	"spring.config.activate.on-profile",
+	"spring.profiles",
+}
+
+// profileNamePattern matches single profile names; profile expressions and lists stay in place
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// SeparateApplicationYamlByProfileRecipe moves profile-gated documents of a
+// multi-document application.yml into application-<profile>.yml files
+type SeparateApplicationYamlByProfileRecipe struct {
+	core.BaseRecipe
+}
+
+// NewSeparateApplicationYamlByProfileRecipe creates a new SeparateApplicationYamlByProfile recipe
+func NewSeparateApplicationYamlByProfileRecipe() *SeparateApplicationYamlByProfileRecipe {
+	return &SeparateApplicationYamlByProfileRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Separate application YAML by profile",
+			Description: "The Spring team's recommendation is to separate profile properties into their own YAML files now.",
+			SourceTypes: []core.FileType{core.YAML},
+		},
+	}
+}
+
+// Apply cannot create the profile files on its own, so the file is returned
+// unchanged; the runner uses ApplyAll instead
+func (r *SeparateApplicationYamlByProfileRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	return sourceFile, nil
+}
+
+// ApplyAll splits every application.yml in the set and returns the resulting files
+func (r *SeparateApplicationYamlByProfileRecipe) ApplyAll(ctx context.Context, sourceFiles []core.SourceFile) ([]core.SourceFile, error) {
+	logger := core.GetExecutionContext(ctx).Logger
+	files := newSourceFileSet(sourceFiles)
+
+	for _, sourceFile := range sourceFiles {
+		if !isApplicationYAML(sourceFile) {
+			continue
+		}
+
+		doc := utils.ParseYAML(sourceFile.GetContent())
+		ext := filepath.Ext(sourceFile.GetPath())
+
+		// Walk backwards so removing a document keeps earlier line indexes valid
+		moved := false
+		for document := doc.DocumentCount() - 1; document >= 0; document-- {
+			profile := documentProfile(doc, document)
+			if profile == "" {
+				continue
+			}
+
+			content := profileDocumentContent(doc, document)
+			profilePath := filepath.Join(filepath.Dir(sourceFile.GetPath()), "application-"+profile+ext)
+			profileFile := files.getOrCreate(profilePath, sourceFile.GetEncoding())
+
+			target := utils.ParseYAML(profileFile.GetContent())
+			// Profile-specific files take precedence over profile documents in application.yml
+			for _, conflict := range mergeYAMLInto(target, target.DocumentCount()-1, content) {
+				logger.Warn("%s already sets %s to %q; ignoring %q from %s",
+					profilePath, conflict.Path, conflict.ExistingValue, conflict.IgnoredValue, sourceFile.GetPath())
+			}
+			profileFile.SetContent(ensureTrailingNewline(target.String()))
+
+			removeYAMLDocument(doc, document)
+			moved = true
+		}
+
+		if moved {
+			content := doc.String()
+			if strings.HasSuffix(sourceFile.GetContent(), "\n") {
+				content = ensureTrailingNewline(content)
+			}
+			sourceFile.SetContent(content)
+		}
+	}
+
+	return files.list(), nil
+}
+
+// isApplicationYAML checks whether the file is a main (not profile-specific) application YAML
+func isApplicationYAML(sourceFile core.SourceFile) bool {
+	name := strings.ToLower(filepath.Base(sourceFile.GetPath()))
+	return sourceFile.GetType() == core.YAML && (name == "application.yml" || name == "application.yaml")
+}
+
+// documentProfile returns the single profile a document is activated for, if any
+func documentProfile(doc *utils.YAMLDocument, document int) string {
+	for _, key := range ProfileActivationKeys {
+		if entry := doc.FindInDocument(key, document); entry != nil && entry.HasValue() {
+			if profileNamePattern.MatchString(entry.Value) {
+				return entry.Value
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// profileDocumentContent returns the text of a document without its profile activation key
+func profileDocumentContent(doc *utils.YAMLDocument, document int) string {
+	start, end := doc.DocumentStarts[document], doc.DocumentEnd(document)
+	profileDoc := utils.ParseYAML(strings.Join(doc.Lines[start:end], "\n"))
+	for _, key := range ProfileActivationKeys {
+		if entry := profileDoc.Find(key); entry != nil {
+			removeYAMLEntry(profileDoc, entry)
+		}
+	}
+	return strings.Trim(profileDoc.String(), "\n")
+}
+
+// removeYAMLDocument deletes a document together with the separator line around it
+func removeYAMLDocument(doc *utils.YAMLDocument, document int) {
+	start, end := doc.DocumentStarts[document], doc.DocumentEnd(document)
+	switch {
+	case document > 0:
+		// Drop the "---" line that opens the document
+		start--
+	case document+1 < doc.DocumentCount():
+		// The first document goes together with the separator that follows it
+		end++
+	}
+	doc.RemoveLines(start, end)
+}
+
+// ensureTrailingNewline terminates content with exactly one newline
+func ensureTrailingNewline(content string) string {
+	return strings.TrimRight(content, "\n") + "\n"
+}
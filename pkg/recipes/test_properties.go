@@ -0,0 +1,196 @@
+package recipes
+
+import (
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// testPropertiesAnnotations are the test annotations whose "properties"
+// attribute holds inline "key=value" properties
+var testPropertiesAnnotations = []string{
+	"SpringBootTest",
+	"TestPropertySource",
+	"DataJpaTest",
+	"DataJdbcTest",
+	"DataR2dbcTest",
+	"DataMongoTest",
+	"DataRedisTest",
+	"DataCassandraTest",
+	"DataCouchbaseTest",
+	"DataElasticsearchTest",
+	"DataLdapTest",
+	"DataNeo4jTest",
+	"JdbcTest",
+	"JooqTest",
+	"JsonTest",
+	"WebMvcTest",
+	"WebFluxTest",
+	"RestClientTest",
+	"GraphQlTest",
+}
+
+// inlineTestProperty is a property defined by Java or Kotlin test code, either
+// inline in a test annotation or registered with a DynamicPropertyRegistry
+type inlineTestProperty struct {
+	key      string
+	keyStart int
+	keyEnd   int
+	// attribute and literal locate a property of a test annotation
+	attribute *utils.AnnotationAttribute
+	literal   int
+	// removal deletes a registration, or one of several properties of a text block
+	removal *textEdit
+}
+
+// findTestProperties returns the properties defined inline by test
+// annotations and by registry.add("key", ...) calls on a DynamicPropertyRegistry
+func findTestProperties(content string, fileType core.FileType) []inlineTestProperty {
+	var properties []inlineTestProperty
+	for _, annotation := range utils.FindAnnotations(content, fileType, testPropertiesAnnotations...) {
+		if attribute := annotation.Attribute("properties"); attribute != nil && len(attribute.Literals) > 0 {
+			properties = append(properties, annotationProperties(content, attribute)...)
+		}
+	}
+
+	for _, registry := range utils.FindVariables(content, fileType, "DynamicPropertyRegistry") {
+		for _, call := range utils.FindMethodCalls(content, fileType, registry, "add") {
+			removal := statementRemoval(content, call.Start, call.End)
+			properties = append(properties, inlineTestProperty{
+				key:      content[call.Argument.Start:call.Argument.End],
+				keyStart: call.Argument.Start,
+				keyEnd:   call.Argument.End,
+				removal:  &removal,
+			})
+		}
+	}
+	return properties
+}
+
+// annotationProperties parses the properties of a "properties" attribute. Each
+// literal holds one property, or several lines of properties in a text block.
+func annotationProperties(content string, attribute *utils.AnnotationAttribute) []inlineTestProperty {
+	var properties []inlineTestProperty
+	for i, literal := range attribute.Literals {
+		doc := utils.ParseProperties(content[literal.Start:literal.End])
+		lineStarts := make([]int, len(doc.Lines)+1)
+		offset := literal.Start
+		for j, line := range doc.Lines {
+			lineStarts[j] = offset
+			offset += len(line) + 1
+		}
+		lineStarts[len(doc.Lines)] = literal.End
+
+		for _, entry := range doc.Entries {
+			property := inlineTestProperty{
+				key:       entry.Key,
+				keyStart:  lineStarts[entry.Line] + entry.KeyStart,
+				keyEnd:    lineStarts[entry.Line] + entry.KeyEnd,
+				attribute: attribute,
+				literal:   i,
+			}
+			if len(doc.Entries) > 1 {
+				property.removal = &textEdit{start: lineStarts[entry.Line], end: lineStarts[entry.End]}
+			}
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
+
+// statementRemoval removes the statement spanning [start, end) up to its
+// semicolon, if any, and its whole line when nothing else is on it
+func statementRemoval(content string, start, end int) textEdit {
+	if semicolon := strings.IndexByte(content[end:], ';'); semicolon >= 0 && strings.TrimSpace(content[end:end+semicolon]) == "" {
+		end += semicolon + 1
+	}
+
+	lineStart := strings.LastIndexByte(content[:start], '\n') + 1
+	lineEnd := len(content)
+	if newline := strings.IndexByte(content[end:], '\n'); newline >= 0 {
+		lineEnd = end + newline + 1
+	}
+	if strings.TrimSpace(content[lineStart:start]) == "" && strings.TrimSpace(content[end:lineEnd]) == "" {
+		return textEdit{start: lineStart, end: lineEnd}
+	}
+	return textEdit{start: start, end: end}
+}
+
+// removeTestProperties deletes properties found by findTestProperties.
+// Literals left without properties are removed from their array together with
+// a separating comma, and an attribute left without literals becomes an empty array.
+func removeTestProperties(content string, all, removed []inlineTestProperty) string {
+	var edits []textEdit
+	remaining := make(map[*utils.AnnotationAttribute]map[int]int)
+	for _, property := range all {
+		if property.attribute != nil {
+			if remaining[property.attribute] == nil {
+				remaining[property.attribute] = make(map[int]int)
+			}
+			remaining[property.attribute][property.literal]++
+		}
+	}
+	for _, property := range removed {
+		if property.attribute == nil {
+			edits = append(edits, *property.removal)
+			continue
+		}
+		remaining[property.attribute][property.literal]--
+		if remaining[property.attribute][property.literal] > 0 {
+			edits = append(edits, *property.removal)
+		}
+	}
+
+	for attribute, counts := range remaining {
+		edits = append(edits, literalRemovals(content, attribute, counts)...)
+	}
+	return applyTextEdits(content, edits)
+}
+
+// literalRemovals removes each run of literals without remaining properties,
+// taking the comma after the run, or before it for a run at the end
+func literalRemovals(content string, attribute *utils.AnnotationAttribute, remaining map[int]int) []textEdit {
+	literals := attribute.Literals
+	empty := func(i int) bool {
+		count, ok := remaining[i]
+		return ok && count == 0
+	}
+
+	var edits []textEdit
+	for i := 0; i < len(literals); i++ {
+		if !empty(i) {
+			continue
+		}
+		j := i
+		for j+1 < len(literals) && empty(j+1) {
+			j++
+		}
+
+		start, _ := literals[i].Bounds()
+		_, end := literals[j].Bounds()
+		switch {
+		case i == 0 && j == len(literals)-1:
+			return []textEdit{{start: attribute.Start, end: attribute.End, text: emptyArray(content[attribute.Start:attribute.End])}}
+		case j+1 < len(literals):
+			end, _ = literals[j+1].Bounds()
+		default:
+			_, start = literals[i-1].Bounds()
+		}
+		edits = append(edits, textEdit{start: start, end: end})
+		i = j
+	}
+	return edits
+}
+
+// emptyArray returns an empty array in the syntax of an annotation value:
+// "{}" in Java, "[]" or "arrayOf()" in Kotlin
+func emptyArray(value string) string {
+	switch {
+	case strings.HasPrefix(value, "["):
+		return "[]"
+	case strings.HasPrefix(value, "arrayOf"):
+		return "arrayOf()"
+	}
+	return "{}"
+}
@@ -0,0 +1,175 @@
+package recipes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// ValidateSpringPropertiesRecipe reports the properties of Spring configuration
+// files that do not match configuration metadata: unknown and deprecated keys,
+// values that cannot be converted to the property type, and values outside
+// those the metadata documents. Findings are recorded in the execution context.
+type ValidateSpringPropertiesRecipe struct {
+	core.BaseRecipe
+	Metadata *utils.MetadataIndex
+	// Fix renames deprecated properties to their replacement before validating
+	Fix bool
+	// Strict also reports unknown keys outside the namespaces of the metadata,
+	// such as application properties under "app"
+	Strict bool
+	// IncludeNonSpringConfig also processes properties and YAML files that are
+	// not Spring configuration, such as i18n bundles or build files
+	IncludeNonSpringConfig bool
+}
+
+// configProperty is a property set by a configuration file
+type configProperty struct {
+	key   string
+	value string
+	line  int
+}
+
+// NewValidateSpringPropertiesRecipe creates a new ValidateSpringProperties recipe
+func NewValidateSpringPropertiesRecipe(metadata *utils.MetadataIndex) *ValidateSpringPropertiesRecipe {
+	return &ValidateSpringPropertiesRecipe{
+		BaseRecipe: core.BaseRecipe{
+			DisplayName: "Validate Spring configuration properties",
+			Description: "Report unknown and deprecated keys, values of the wrong type and values outside the " +
+				"documented ones in Properties and YAML files, checked against Spring configuration metadata.",
+			SourceTypes: []core.FileType{core.Properties, core.YAML},
+		},
+		Metadata: metadata,
+	}
+}
+
+// Apply executes the recipe on the provided source file
+func (r *ValidateSpringPropertiesRecipe) Apply(ctx context.Context, sourceFile core.SourceFile) (core.SourceFile, error) {
+	if !acceptsConfigKind(sourceFile, r.IncludeNonSpringConfig) {
+		return sourceFile, nil
+	}
+	if sourceFile.GetType() != core.Properties && sourceFile.GetType() != core.YAML {
+		return sourceFile, nil
+	}
+
+	if r.Fix {
+		if err := r.fixDeprecations(ctx, sourceFile); err != nil {
+			return sourceFile, err
+		}
+	}
+
+	properties, err := configProperties(sourceFile)
+	if err != nil {
+		return sourceFile, err
+	}
+	executionContext := core.GetExecutionContext(ctx)
+	for _, property := range properties {
+		r.validate(executionContext, sourceFile, property)
+	}
+
+	return sourceFile, nil
+}
+
+// validate reports the problems of a single property
+func (r *ValidateSpringPropertiesRecipe) validate(executionContext *core.ExecutionContext, sourceFile core.SourceFile, property configProperty) {
+	report := func(severity core.Severity, message string, args ...interface{}) {
+		executionContext.Report(sourceFile, property.line, core.Finding{
+			Key:      property.key,
+			Severity: severity,
+			Message:  fmt.Sprintf(message, args...),
+		})
+	}
+
+	binding, ok := r.Metadata.Resolve(property.key)
+	if !ok {
+		if r.Strict || r.Metadata.InNamespace(property.key) {
+			report(core.Warning, "Unknown property '%s'", property.key)
+		}
+		return
+	}
+
+	if binding.Property.IsDeprecated() {
+		severity, message := core.Warning, fmt.Sprintf("Property '%s' is deprecated", property.key)
+		if deprecation := binding.Property.Deprecation; deprecation != nil {
+			if deprecation.Level == "error" {
+				severity, message = core.Error, fmt.Sprintf("Property '%s' is no longer supported", property.key)
+			}
+			if deprecation.Replacement != "" {
+				message += fmt.Sprintf(", use '%s' instead", deprecation.Replacement)
+			}
+			if deprecation.Reason != "" {
+				message += ": " + deprecation.Reason
+			}
+		}
+		report(severity, "%s", message)
+	}
+
+	if err := binding.ValidateValue(property.value); err != nil {
+		report(core.Error, "Invalid value for '%s': %v", property.key, err)
+	} else if !binding.IsDocumentedValue(property.value) {
+		report(core.Warning, "Value %q of '%s' is not one of the documented values: %s",
+			property.value, property.key, strings.Join(binding.DocumentedValues(), ", "))
+	}
+}
+
+// fixDeprecations renames the deprecated properties of a file that have a replacement
+func (r *ValidateSpringPropertiesRecipe) fixDeprecations(ctx context.Context, sourceFile core.SourceFile) error {
+	properties, err := configProperties(sourceFile)
+	if err != nil {
+		return err
+	}
+
+	logger := core.GetExecutionContext(ctx).Logger
+	fixed := make(map[string]bool)
+	for _, property := range properties {
+		binding, ok := r.Metadata.Resolve(property.key)
+		if !ok || binding.Property.Deprecation == nil || fixed[binding.Property.Name] {
+			continue
+		}
+		replacement := strings.TrimSpace(binding.Property.Deprecation.Replacement)
+		if replacement == "" {
+			continue
+		}
+		fixed[binding.Property.Name] = true
+
+		changeKey := NewChangeSpringPropertyKeyRecipe(binding.Property.Name, replacement, nil)
+		changeKey.IncludeNonSpringConfig = r.IncludeNonSpringConfig
+		if _, err := changeKey.Apply(ctx, sourceFile); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", binding.Property.Name, err)
+		}
+		logger.Info("%s: replaced deprecated property %s with %s", sourceFile.GetPath(), binding.Property.Name, replacement)
+	}
+	return nil
+}
+
+// configProperties returns the properties a Properties or YAML file sets, with
+// the values Spring binds and the line of each
+func configProperties(sourceFile core.SourceFile) ([]configProperty, error) {
+	var properties []configProperty
+	if sourceFile.GetType() == core.Properties {
+		doc := utils.ParseProperties(sourceFile.GetContent())
+		for _, entry := range doc.Entries {
+			properties = append(properties, configProperty{
+				key:   entry.Key,
+				value: utils.UnescapePropertiesValue(entry.Value),
+				line:  entry.Line,
+			})
+		}
+		return properties, nil
+	}
+
+	doc := utils.ParseYAML(sourceFile.GetContent())
+	for document := 0; document < doc.DocumentCount(); document++ {
+		flattened, err := doc.FlattenDocument(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", sourceFile.GetPath(), err)
+		}
+		for _, property := range flattened {
+			properties = append(properties, configProperty{key: property.Key, value: property.Value, line: property.Line})
+		}
+	}
+	return properties, nil
+}
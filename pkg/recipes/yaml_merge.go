@@ -0,0 +1,156 @@
+package recipes
+
+import (
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// mergeConflict is a property defined both in a merge target and in the content merged into it
+type mergeConflict struct {
+	Path          string
+	ExistingValue string
+	IgnoredValue  string
+}
+
+// mergeYAMLInto merges YAML content into one document of target, sharing parent
+// mappings that already exist. Properties already defined in target keep their
+// value; the conflicting definitions are dropped and returned.
+func mergeYAMLInto(target *utils.YAMLDocument, document int, content string) []mergeConflict {
+	addition := utils.ParseYAML(strings.Trim(content, "\n"))
+
+	var conflicts []mergeConflict
+	for {
+		existing, added := findYAMLConflict(target, document, addition)
+		if added == nil {
+			break
+		}
+		if existing.RawValue != added.RawValue || existing.End-existing.Line > 1 || added.End-added.Line > 1 {
+			conflicts = append(conflicts, mergeConflict{
+				Path:          added.Path,
+				ExistingValue: yamlEntryText(target, existing),
+				IgnoredValue:  yamlEntryText(addition, added),
+			})
+		}
+		removeYAMLEntry(addition, added)
+	}
+
+	if strings.TrimSpace(addition.String()) == "" {
+		return conflicts
+	}
+
+	// Insert the remaining lines after the last content of the document
+	start, end := target.DocumentStarts[document], target.DocumentEnd(document)
+	for end > start && strings.TrimSpace(target.Lines[end-1]) == "" {
+		end--
+	}
+	lines := append([]string{}, target.Lines[:end]...)
+	lines = append(lines, strings.Split(strings.Trim(addition.String(), "\n"), "\n")...)
+	if end == len(target.Lines) {
+		lines = append(lines, "")
+	}
+	lines = append(lines, target.Lines[end:]...)
+	merged := utils.ParseYAML(strings.Join(lines, "\n"))
+
+	for {
+		first, second := findDuplicateYAMLEntries(merged)
+		if first == nil {
+			break
+		}
+		mergeYAMLEntries(merged, first, second)
+	}
+
+	target.SetLines(merged.Lines)
+	return conflicts
+}
+
+// findYAMLConflict finds an entry of the addition that cannot be merged because
+// target already defines a value at its path, or a value above or below it
+func findYAMLConflict(target *utils.YAMLDocument, document int, addition *utils.YAMLDocument) (*utils.YAMLEntry, *utils.YAMLEntry) {
+	for _, added := range addition.Entries {
+		existing := target.FindInDocument(added.Path, document)
+		if existing == nil {
+			continue
+		}
+		// Both sides are plain mappings: their children are merged instead
+		if isYAMLMapping(existing) && isYAMLMapping(added) {
+			continue
+		}
+		return existing, added
+	}
+	return nil, nil
+}
+
+// isYAMLMapping reports whether an entry holds nested keys rather than a value or sequence
+func isYAMLMapping(entry *utils.YAMLEntry) bool {
+	return entry.RawValue == "" && !entry.IsSequence() && (len(entry.Children) > 0 || entry.End == entry.Line+1)
+}
+
+// yamlEntryText returns the value of an entry for reporting, or its first line for nested content
+func yamlEntryText(doc *utils.YAMLDocument, entry *utils.YAMLEntry) string {
+	if entry.HasValue() {
+		return entry.Value
+	}
+	return strings.TrimSpace(strings.Join(doc.Lines[entry.Line+1:entry.End], " "))
+}
+
+// appendYAMLDocument adds content to target as a new document after a "---" separator
+func appendYAMLDocument(target *utils.YAMLDocument, content string) {
+	end := len(target.Lines)
+	for end > 0 && strings.TrimSpace(target.Lines[end-1]) == "" {
+		end--
+	}
+	lines := append([]string{}, target.Lines[:end]...)
+	if end > 0 {
+		lines = append(lines, "---")
+	}
+	lines = append(lines, strings.Split(strings.Trim(content, "\n"), "\n")...)
+	target.SetLines(append(lines, ""))
+}
+
+// mergeYAMLDocuments merges each document of addition into the target document
+// activated for the same profiles, appending documents that have no counterpart
+func mergeYAMLDocuments(target, addition *utils.YAMLDocument) []mergeConflict {
+	var conflicts []mergeConflict
+	for document := 0; document < addition.DocumentCount(); document++ {
+		start, end := addition.DocumentStarts[document], addition.DocumentEnd(document)
+		content := strings.Trim(strings.Join(addition.Lines[start:end], "\n"), "\n")
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		activation := documentActivation(addition, document)
+		targetDocument := findYAMLDocument(target, activation)
+		if targetDocument < 0 {
+			appendYAMLDocument(target, content)
+			continue
+		}
+		if activation != "" {
+			// The target document already carries the activation key
+			content = profileDocumentContent(addition, document)
+		}
+		conflicts = append(conflicts, mergeYAMLInto(target, targetDocument, content)...)
+	}
+	return conflicts
+}
+
+// documentActivation returns the profile expression a document is activated for,
+// or "" for a document that always applies
+func documentActivation(doc *utils.YAMLDocument, document int) string {
+	for _, key := range ProfileActivationKeys {
+		if entry := doc.FindInDocument(key, document); entry != nil && entry.HasValue() {
+			return entry.Value
+		}
+	}
+	return ""
+}
+
+// findYAMLDocument returns the first document with the given activation, or -1
+func findYAMLDocument(doc *utils.YAMLDocument, activation string) int {
+	for document := 0; document < doc.DocumentCount(); document++ {
+		if documentActivation(doc, document) == activation {
+			return document
+		}
+	}
+	return -1
+}
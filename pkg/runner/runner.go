@@ -0,0 +1,412 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+	"github.com/openrewrite/rewrite-spring-go/pkg/utils"
+)
+
+// DefaultPatterns are the file patterns searched when none are given; recipes
+// decide from each file's classification whether it is Spring configuration
+// they should modify
+var DefaultPatterns = []string{
+	"**/*.properties",
+	"**/*.yml",
+	"**/*.yaml",
+	"**/*.java",
+	"**/*.kt",
+	"**/Dockerfile",
+	"**/Dockerfile.*",
+	"**/*.dockerfile",
+	"**/Containerfile",
+	"**/.env",
+	"**/.env.*",
+	"**/*.env",
+}
+
+// Runner discovers source files, applies a recipe to them and writes the results
+type Runner struct {
+	SourcePath string
+	OutputPath string
+	Patterns   []string
+	DryRun     bool
+	Backup     bool
+	Logger     core.Logger
+	// EmbeddedConfigPaths are JSONPaths such as "$.app.config" of YAML keys
+	// holding Spring configuration, in addition to keys named like application.yml
+	EmbeddedConfigPaths []string
+}
+
+// Result lists the files a run modified, generated and deleted, and the
+// findings the recipe reported
+type Result struct {
+	Modified  []string
+	Generated []string
+	Deleted   []string
+	Findings  []core.Finding
+}
+
+// Changed returns the total number of files affected by the run
+func (r *Result) Changed() int {
+	return len(r.Modified) + len(r.Generated) + len(r.Deleted)
+}
+
+// NewRunner creates a runner writing back into the source directory
+func NewRunner(sourcePath string, logger core.Logger) *Runner {
+	return &Runner{
+		SourcePath: sourcePath,
+		OutputPath: sourcePath,
+		Patterns:   DefaultPatterns,
+		Backup:     true,
+		Logger:     logger,
+	}
+}
+
+// Run applies the recipe to every matching source file. Recipes implementing
+// core.ScanningRecipe see all files at once and may create or delete files.
+func (r *Runner) Run(ctx context.Context, recipe core.Recipe) (*Result, error) {
+	sourceFiles, err := r.LoadSourceFiles(recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	// Embedded configuration cannot be split, merged or converted into files of its own
+	var embedded []*core.EmbeddedSourceFile
+	if _, scanning := recipe.(core.ScanningRecipe); !scanning {
+		embedded, err = r.LoadEmbeddedSourceFiles(recipe, sourceFiles)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	originals := make(map[string]string, len(sourceFiles)+len(embedded))
+	for _, sourceFile := range sourceFiles {
+		originals[sourceFile.GetPath()] = sourceFile.GetContent()
+	}
+	for _, sourceFile := range embedded {
+		originals[sourceFile.GetPath()] = sourceFile.GetContent()
+	}
+
+	executionContext := &core.ExecutionContext{
+		Options: map[string]interface{}{},
+		Logger:  r.Logger,
+	}
+	ctx = core.WithExecutionContext(ctx, executionContext)
+
+	var results []core.SourceFile
+	if scanning, ok := recipe.(core.ScanningRecipe); ok {
+		results, err = scanning.ApplyAll(ctx, sourceFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply recipe: %w", err)
+		}
+	} else {
+		for _, sourceFile := range sourceFiles {
+			path := sourceFile.GetPath()
+			transformed, err := recipe.Apply(ctx, sourceFile)
+			if err != nil {
+				r.Logger.Error("Failed to apply recipe to %s: %v", path, err)
+				// Keep the file as it was on disk
+				sourceFile.SetContent(originals[path])
+				transformed = sourceFile
+			}
+			results = append(results, transformed)
+		}
+		for _, sourceFile := range embedded {
+			if _, err := recipe.Apply(ctx, sourceFile); err != nil {
+				r.Logger.Error("Failed to apply recipe to %s: %v", sourceFile.GetPath(), err)
+				sourceFile.SetContent(originals[sourceFile.GetPath()])
+			}
+		}
+		results = r.writeBackEmbedded(embedded, originals, results)
+	}
+
+	result := &Result{Findings: executionContext.Findings}
+	seen := make(map[string]bool, len(results))
+	for _, sourceFile := range results {
+		path := sourceFile.GetPath()
+		seen[path] = true
+
+		original, existed := originals[path]
+		switch {
+		case !existed && !utils.FileExists(path):
+			if r.write(sourceFile, true) {
+				result.Generated = append(result.Generated, path)
+			}
+		case !existed || sourceFile.GetContent() != original:
+			// Files outside the discovered set that a recipe rewrote count as modified
+			if r.write(sourceFile, false) {
+				result.Modified = append(result.Modified, path)
+			}
+		}
+	}
+
+	for _, sourceFile := range sourceFiles {
+		path := sourceFile.GetPath()
+		if !seen[path] && r.delete(path) {
+			result.Deleted = append(result.Deleted, path)
+		}
+	}
+
+	return result, nil
+}
+
+// LoadSourceFiles discovers and parses the files of the types the recipe visits
+func (r *Runner) LoadSourceFiles(recipe core.Recipe) ([]core.SourceFile, error) {
+	patterns := r.Patterns
+	if len(patterns) == 0 {
+		patterns = DefaultPatterns
+	}
+
+	configFiles, err := utils.FindSpringConfigFiles(r.SourcePath, patterns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find configuration files: %w", err)
+	}
+
+	r.Logger.Info("Found %d configuration files", len(configFiles))
+
+	var sourceFiles []core.SourceFile
+	for _, filePath := range configFiles {
+		// Skip files of a type the recipe does not visit
+		fileType := utils.DetermineFileType(filePath)
+		if !core.Visits(recipe, fileType) {
+			r.Logger.Debug("Skipping %s file: %s", fileType, filePath)
+			continue
+		}
+
+		r.Logger.Debug("Processing file (%s): %s", utils.ClassifyConfigFile(filePath), filePath)
+
+		sourceFile, err := utils.LoadSourceFile(filePath)
+		if errors.Is(err, core.ErrUnsupportedFile) {
+			r.Logger.Debug("Skipping unsupported file: %s", filePath)
+			continue
+		}
+		if err != nil {
+			r.Logger.Error("Failed to load file %s: %v", filePath, err)
+			continue
+		}
+		sourceFiles = append(sourceFiles, sourceFile)
+	}
+
+	return sourceFiles, nil
+}
+
+// LoadEmbeddedSourceFiles extracts the Spring configuration embedded in YAML
+// files that are not Spring configuration themselves, such as Kubernetes
+// ConfigMaps and Helm values, for the file types the recipe visits
+func (r *Runner) LoadEmbeddedSourceFiles(recipe core.Recipe, sourceFiles []core.SourceFile) ([]*core.EmbeddedSourceFile, error) {
+	paths, err := r.embeddedConfigPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	hosts, err := r.loadEmbeddingHosts(sourceFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var embedded []*core.EmbeddedSourceFile
+	for _, host := range hosts {
+		for _, config := range utils.FindEmbeddedConfigs(host.GetContent(), paths) {
+			if !core.Visits(recipe, config.Type) {
+				continue
+			}
+			path := host.GetPath() + "#" + config.Key
+			if config.Document > 0 {
+				path = fmt.Sprintf("%s#%d/%s", host.GetPath(), config.Document, config.Key)
+			}
+			r.Logger.Debug("Found embedded %s configuration: %s", config.Type, path)
+
+			embedded = append(embedded, &core.EmbeddedSourceFile{
+				SpringConfigFile: core.SpringConfigFile{
+					Path:     path,
+					Content:  utils.EmbeddedConfigContent(host.GetContent(), config),
+					Type:     config.Type,
+					Kind:     config.Kind,
+					Encoding: host.GetEncoding(),
+				},
+				Host:     host,
+				Key:      config.Key,
+				Document: config.Document,
+				Line:     config.Start,
+			})
+		}
+	}
+	return embedded, nil
+}
+
+// embeddedConfigPaths parses the configured JSONPaths of embedded configuration
+func (r *Runner) embeddedConfigPaths() ([][]string, error) {
+	paths := make([][]string, 0, len(r.EmbeddedConfigPaths))
+	for _, path := range r.EmbeddedConfigPaths {
+		keys, err := utils.ParseJSONPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid embedded configuration path: %w", err)
+		}
+		paths = append(paths, keys)
+	}
+	return paths, nil
+}
+
+// loadEmbeddingHosts returns the YAML files that may embed Spring configuration,
+// reusing the loaded source files and reading the others from disk
+func (r *Runner) loadEmbeddingHosts(sourceFiles []core.SourceFile) ([]core.SourceFile, error) {
+	patterns := r.Patterns
+	if len(patterns) == 0 {
+		patterns = DefaultPatterns
+	}
+	filePaths, err := utils.FindSpringConfigFiles(r.SourcePath, patterns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find configuration files: %w", err)
+	}
+
+	loaded := make(map[string]core.SourceFile, len(sourceFiles))
+	for _, sourceFile := range sourceFiles {
+		loaded[sourceFile.GetPath()] = sourceFile
+	}
+
+	var hosts []core.SourceFile
+	for _, filePath := range filePaths {
+		if utils.DetermineFileType(filePath) != core.YAML {
+			continue
+		}
+		host, ok := loaded[filePath]
+		if !ok {
+			if host, err = utils.LoadSourceFile(filePath); err != nil {
+				r.Logger.Error("Failed to load file %s: %v", filePath, err)
+				continue
+			}
+		}
+		if !host.GetConfigKind().IsSpringConfig() {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
+
+// writeBackEmbedded replaces the embedded configuration that the recipe changed
+// in its host, adding hosts the recipe did not visit itself to the results
+func (r *Runner) writeBackEmbedded(embedded []*core.EmbeddedSourceFile, originals map[string]string, results []core.SourceFile) []core.SourceFile {
+	inResults := make(map[string]bool, len(results))
+	for _, sourceFile := range results {
+		inResults[sourceFile.GetPath()] = true
+	}
+
+	// The paths were validated when the embedded files were loaded
+	paths, _ := r.embeddedConfigPaths()
+
+	for _, sourceFile := range embedded {
+		original := originals[sourceFile.GetPath()]
+		if sourceFile.GetContent() == original {
+			continue
+		}
+
+		// The host may have changed around the embedded content, so it is located again
+		host := sourceFile.Host
+		var config *utils.EmbeddedConfig
+		for _, candidate := range utils.FindEmbeddedConfigs(host.GetContent(), paths) {
+			if candidate.Key == sourceFile.Key && candidate.Document == sourceFile.Document {
+				config = &candidate
+				break
+			}
+		}
+		if config == nil || utils.EmbeddedConfigContent(host.GetContent(), *config) != original {
+			r.Logger.Warn("Skipping %s: the embedded configuration changed in its host file", sourceFile.GetPath())
+			continue
+		}
+
+		host.SetContent(utils.ReplaceEmbeddedConfig(host.GetContent(), *config, sourceFile.GetContent()))
+		if !inResults[host.GetPath()] {
+			results = append(results, host)
+			inResults[host.GetPath()] = true
+		}
+	}
+	return results
+}
+
+// write saves a modified or generated file, reporting whether it counts as changed
+func (r *Runner) write(sourceFile core.SourceFile, generated bool) bool {
+	filePath := sourceFile.GetPath()
+	if generated {
+		r.Logger.Info("Created: %s", filePath)
+	} else {
+		r.Logger.Info("Modified: %s", filePath)
+	}
+
+	if r.DryRun {
+		if generated {
+			r.Logger.Info("DRY RUN: Would create file %s", filePath)
+		} else {
+			r.Logger.Info("DRY RUN: Would modify file %s", filePath)
+		}
+		return true
+	}
+
+	// Create backup if requested
+	if r.Backup && !generated {
+		if err := utils.BackupFile(filePath); err != nil {
+			r.Logger.Warn("Failed to create backup for %s: %v", filePath, err)
+		}
+	}
+
+	outputFilePath, err := r.outputFilePath(filePath)
+	if err != nil {
+		r.Logger.Error("Failed to get relative path for %s: %v", filePath, err)
+		return false
+	}
+
+	// Save transformed file
+	if err := utils.SaveSourceFile(sourceFile, outputFilePath); err != nil {
+		r.Logger.Error("Failed to save file %s: %v", outputFilePath, err)
+		return false
+	}
+
+	r.Logger.Debug("Saved transformed file: %s", outputFilePath)
+	return true
+}
+
+// delete removes a file the recipe dropped, reporting whether it counts as changed
+func (r *Runner) delete(filePath string) bool {
+	r.Logger.Info("Deleted: %s", filePath)
+
+	if r.DryRun {
+		r.Logger.Info("DRY RUN: Would delete file %s", filePath)
+		return true
+	}
+
+	outputFilePath, err := r.outputFilePath(filePath)
+	if err != nil {
+		r.Logger.Error("Failed to get relative path for %s: %v", filePath, err)
+		return false
+	}
+
+	// When writing to a separate output directory the source tree is left alone
+	if outputFilePath == filePath && r.Backup {
+		if err := utils.BackupFile(filePath); err != nil {
+			r.Logger.Warn("Failed to create backup for %s: %v", filePath, err)
+		}
+	}
+
+	if err := os.Remove(outputFilePath); err != nil && !os.IsNotExist(err) {
+		r.Logger.Error("Failed to delete file %s: %v", outputFilePath, err)
+		return false
+	}
+
+	return true
+}
+
+// outputFilePath maps a path below the source directory to the output directory
+func (r *Runner) outputFilePath(filePath string) (string, error) {
+	if r.OutputPath == "" || r.OutputPath == r.SourcePath {
+		return filePath, nil
+	}
+	relPath, err := filepath.Rel(r.SourcePath, filePath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(r.OutputPath, relPath), nil
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+// SpringCloudConfigRepoPatterns are path globs identifying files served by a
+// Spring Cloud Config server, such as {application}-{profile}.yml in a config repo
+var SpringCloudConfigRepoPatterns = []string{
+	"**/config-repo/**",
+	"**/cloud-config/**",
+	"**/config-server-repo/**",
+}
+
+var (
+	// springConfigPattern matches application and bootstrap files, capturing an optional profile
+	springConfigPattern = regexp.MustCompile(`^(application|bootstrap)(-[^.]+)?\.(properties|yml|yaml)$`)
+
+	// localeSuffixPattern matches resource bundle names such as messages_de or errors_pt_BR
+	localeSuffixPattern = regexp.MustCompile(`_[a-z]{2,3}(_[A-Z]{2})?(_\w+)?\.properties$`)
+
+	buildConfigNames = []string{
+		"gradle.properties",
+		"gradle-wrapper.properties",
+		"maven-wrapper.properties",
+		"local.properties",
+		"sonar-project.properties",
+		".gitlab-ci.yml",
+		".travis.yml",
+		"azure-pipelines.yml",
+		"bitbucket-pipelines.yml",
+		"cloudbuild.yaml",
+		"cloudbuild.yml",
+		"codecov.yml",
+		"dependabot.yml",
+		"renovate.yml",
+	}
+
+	buildConfigPathPatterns = []string{
+		"**/.github/**",
+		"**/.circleci/**",
+		"**/.buildkite/**",
+	}
+
+	// deploymentNamePatterns match files that set environment variables for an application
+	deploymentNamePatterns = []string{
+		"dockerfile",
+		"dockerfile.*",
+		"*.dockerfile",
+		"containerfile",
+		".env",
+		".env.*",
+		"*.env",
+		"docker-compose*.yml",
+		"docker-compose*.yaml",
+		"compose*.yml",
+		"compose*.yaml",
+	}
+
+	kubernetesPathPatterns = []string{
+		"**/k8s/**",
+		"**/kubernetes/**",
+		"**/openshift/**",
+	}
+
+	// kubernetesKindPattern and kubernetesAPIVersionPattern recognise the
+	// top-level fields of a Kubernetes manifest
+	kubernetesKindPattern       = regexp.MustCompile(`(?m)^kind:\s*[A-Z]\w*\s*$`)
+	kubernetesAPIVersionPattern = regexp.MustCompile(`(?m)^apiVersion:\s*\S+\s*$`)
+
+	// testConfigNamePattern matches the names of Spring configuration in test
+	// resources that springConfigPattern leaves out, such as application-it.test.yml
+	// or applicationTest.properties
+	testConfigNamePattern = regexp.MustCompile(`^(application|bootstrap)[^/]*\.(properties|yml|yaml)$`)
+
+	testResourcePathPatterns = []string{
+		"**/src/test/resources/**",
+		"**/src/*Test/resources/**",
+	}
+)
+
+// ClassifyConfigFile determines the role a configuration file plays in a project from
+// its path, porting the idea behind the Java IsPossibleSpringConfigFile visitor
+func ClassifyConfigFile(filePath string) core.ConfigKind {
+	slashPath := "/" + strings.TrimPrefix(filepath.ToSlash(filePath), "/")
+	fileName := filepath.Base(slashPath)
+	lowerName := strings.ToLower(fileName)
+
+	ext := filepath.Ext(lowerName)
+	if ext != ".yml" && ext != ".yaml" && !strings.HasSuffix(lowerName, ".backup") &&
+		matchesAnyGlob(lowerName, deploymentNamePatterns) {
+		return core.DeploymentConfig
+	}
+	if ext != ".properties" && ext != ".yml" && ext != ".yaml" {
+		return core.OtherConfig
+	}
+
+	for _, name := range buildConfigNames {
+		if lowerName == name {
+			return core.BuildConfig
+		}
+	}
+	if matchesAnyGlob(slashPath, buildConfigPathPatterns) {
+		return core.BuildConfig
+	}
+
+	if matchesAnyGlob(slashPath, SpringCloudConfigRepoPatterns) {
+		return core.SpringCloudConfigRepo
+	}
+
+	if match := springConfigPattern.FindStringSubmatch(lowerName); match != nil {
+		if match[2] != "" {
+			return core.SpringProfileConfig
+		}
+		return core.SpringApplicationConfig
+	}
+
+	if ext == ".properties" {
+		if strings.HasPrefix(lowerName, "messages") ||
+			strings.Contains(lowerName, "validationmessages") ||
+			strings.Contains(slashPath, "/i18n/") ||
+			localeSuffixPattern.MatchString(fileName) {
+			return core.I18nBundle
+		}
+	}
+
+	// Test resources named like Spring configuration are loaded through
+	// @TestPropertySource and the like; others, such as junit-platform.properties
+	// or log4j2-test.properties, belong to other libraries
+	if testConfigNamePattern.MatchString(lowerName) && matchesAnyGlob(slashPath, testResourcePathPatterns) {
+		return core.SpringTestConfig
+	}
+
+	if ext != ".properties" && (matchesAnyGlob(lowerName, deploymentNamePatterns) ||
+		matchesAnyGlob(slashPath, kubernetesPathPatterns)) {
+		return core.DeploymentConfig
+	}
+
+	return core.OtherConfig
+}
+
+// ClassifySourceFile determines the role of a parsed file like ClassifyConfigFile,
+// also recognising Kubernetes manifests by their content wherever they are kept
+func ClassifySourceFile(filePath string, fileType core.FileType, content string) core.ConfigKind {
+	kind := ClassifyConfigFile(filePath)
+	if kind == core.OtherConfig && fileType == core.YAML && IsKubernetesManifest(content) {
+		return core.DeploymentConfig
+	}
+	return kind
+}
+
+// IsKubernetesManifest reports whether YAML content declares Kubernetes resources
+func IsKubernetesManifest(content string) bool {
+	return kubernetesKindPattern.MatchString(content) && kubernetesAPIVersionPattern.MatchString(content)
+}
+
+// matchesAnyGlob checks whether the path matches at least one of the patterns
+func matchesAnyGlob(path string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := MatchGlob(path, pattern); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+func TestClassifyConfigFile(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected core.ConfigKind
+	}{
+		{"src/main/resources/application.yml", core.SpringApplicationConfig},
+		{"src/main/resources/application-prod.properties", core.SpringProfileConfig},
+		{"src/main/resources/bootstrap.yml", core.SpringApplicationConfig},
+		{"src/test/resources/application-test.yml", core.SpringProfileConfig},
+		{"src/test/resources/application.test.properties", core.SpringTestConfig},
+		{"src/test/resources/applicationTest.yml", core.SpringTestConfig},
+		{"src/integrationTest/resources/bootstrap_it.yaml", core.SpringTestConfig},
+		{"src/test/resources/junit-platform.properties", core.OtherConfig},
+		{"src/test/resources/log4j2-test.properties", core.OtherConfig},
+		{"src/test/resources/test.properties", core.OtherConfig},
+		{"src/test/resources/messages_de.properties", core.I18nBundle},
+		{"src/main/resources/log4j2.yml", core.OtherConfig},
+		{"gradle.properties", core.BuildConfig},
+		{"config-repo/orders-prod.yml", core.SpringCloudConfigRepo},
+		{"docker-compose.yml", core.DeploymentConfig},
+		{"Dockerfile", core.DeploymentConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if actual := ClassifyConfigFile(tt.path); actual != tt.expected {
+				t.Errorf("ClassifyConfigFile(%q) = %v, want %v", tt.path, actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigurationMetadata holds the groups, properties and value hints described
+// by Spring Boot configuration metadata, as found in
+// META-INF/spring-configuration-metadata.json
+type ConfigurationMetadata struct {
+	Groups     []*ConfigurationGroup    `json:"groups"`
+	Properties []*ConfigurationProperty `json:"properties"`
+	Hints      []*ConfigurationHint     `json:"hints"`
+	// Enums holds the constants of the enum types of properties by type name,
+	// as read by LoadEnumConstants
+	Enums map[string][]string `json:"-"`
+}
+
+// ConfigurationGroup is a group of properties, typically a @ConfigurationProperties class
+type ConfigurationGroup struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// ConfigurationProperty describes a single configuration property
+type ConfigurationProperty struct {
+	Name        string       `json:"name"`
+	Type        string       `json:"type,omitempty"`
+	Deprecated  bool         `json:"deprecated,omitempty"`
+	Deprecation *Deprecation `json:"deprecation,omitempty"`
+	// Source is the file, or the jar entry, the property was read from
+	Source string `json:"-"`
+}
+
+// Deprecation describes why a property is deprecated and what replaces it
+type Deprecation struct {
+	// Level is "warning" while the property is still bound, "error" once it is no longer supported
+	Level       string `json:"level,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// ConfigurationHint documents the values of a property; hints named
+// "<property>.values" apply to the values of a map-valued property
+type ConfigurationHint struct {
+	Name      string          `json:"name"`
+	Values    []HintValue     `json:"values,omitempty"`
+	Providers []ValueProvider `json:"providers,omitempty"`
+}
+
+// HintValue is a documented value of a property
+type HintValue struct {
+	Value       interface{} `json:"value"`
+	Description string      `json:"description,omitempty"`
+}
+
+// ValueProvider names the source of further values of a property, e.g. "any"
+// when values other than the documented ones are valid too
+type ValueProvider struct {
+	Name string `json:"name"`
+}
+
+// IsDeprecated reports whether the property is deprecated
+func (p *ConfigurationProperty) IsDeprecated() bool {
+	return p.Deprecation != nil || p.Deprecated
+}
+
+// IsConfigurationMetadataFile reports whether a file name is that of a
+// configuration metadata file, including additional-spring-configuration-metadata.json
+func IsConfigurationMetadataFile(name string) bool {
+	return strings.HasSuffix(path.Base(filepath.ToSlash(name)), "spring-configuration-metadata.json")
+}
+
+// isArchiveFile reports whether a file is a jar, or a zip holding jars
+func isArchiveFile(name string) bool {
+	ext := strings.ToLower(path.Ext(filepath.ToSlash(name)))
+	return ext == ".jar" || ext == ".zip"
+}
+
+// LoadConfigurationMetadata reads the configuration metadata of jar files,
+// directories and metadata JSON files. Directories are searched for metadata
+// files and jars; jars are searched for META-INF metadata, including that of
+// the jars nested in them, such as the BOOT-INF/lib of an executable jar.
+// The properties are returned in the order the paths are given, and files in
+// lexical order within them.
+func LoadConfigurationMetadata(paths []string) (*ConfigurationMetadata, error) {
+	metadata := &ConfigurationMetadata{}
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read configuration metadata from %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			if err := loadMetadataFile(metadata, root); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !(IsConfigurationMetadataFile(filePath) || isArchiveFile(filePath)) {
+				return nil
+			}
+			return loadMetadataFile(metadata, filePath)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+		}
+	}
+	return metadata, nil
+}
+
+// loadMetadataFile adds the properties of a metadata JSON file or a jar
+func loadMetadataFile(metadata *ConfigurationMetadata, filePath string) error {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	if isArchiveFile(filePath) {
+		return loadMetadataArchive(metadata, filePath, content)
+	}
+	return parseMetadataJSON(metadata, filePath, content)
+}
+
+// loadMetadataArchive adds the properties of the metadata files in a jar and in the jars it contains
+func loadMetadataArchive(metadata *ConfigurationMetadata, name string, content []byte) error {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return fmt.Errorf("failed to open archive %s: %w", name, err)
+	}
+	for _, file := range archive.File {
+		isMetadata := strings.HasPrefix(file.Name, "META-INF/") && IsConfigurationMetadataFile(file.Name)
+		if file.FileInfo().IsDir() || !(isMetadata || isArchiveFile(file.Name)) {
+			continue
+		}
+		entryContent, err := readArchiveEntry(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s in %s: %w", file.Name, name, err)
+		}
+		entryName := name + "!/" + file.Name
+		if isMetadata {
+			err = parseMetadataJSON(metadata, entryName, entryContent)
+		} else {
+			err = loadMetadataArchive(metadata, entryName, entryContent)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readArchiveEntry returns the content of a file in a zip archive
+func readArchiveEntry(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// parseMetadataJSON adds the groups, properties and hints of a metadata JSON document
+func parseMetadataJSON(metadata *ConfigurationMetadata, name string, content []byte) error {
+	var parsed ConfigurationMetadata
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		return fmt.Errorf("failed to parse configuration metadata %s: %w", name, err)
+	}
+	for _, group := range parsed.Groups {
+		if group != nil && group.Name != "" {
+			metadata.Groups = append(metadata.Groups, group)
+		}
+	}
+	for _, hint := range parsed.Hints {
+		if hint != nil && hint.Name != "" {
+			metadata.Hints = append(metadata.Hints, hint)
+		}
+	}
+	for _, property := range parsed.Properties {
+		if property == nil || property.Name == "" {
+			continue
+		}
+		property.Source = name
+		metadata.Properties = append(metadata.Properties, property)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+// EmbeddedConfig is Spring configuration embedded in a YAML file: a literal
+// block scalar or mapping under a key named like application.yml, as in the
+// data of a Kubernetes ConfigMap, or a block scalar or mapping at a configured
+// path, such as the Spring settings of a Helm chart's values.yaml
+type EmbeddedConfig struct {
+	Key      string // keys leading to the configuration joined by "/", e.g. "data/application.yml"
+	Type     core.FileType
+	Kind     core.ConfigKind
+	Document int // index of the YAML document holding the key
+	Start    int // first line of the embedded content
+	End      int // one past the last line of the embedded content
+	Indent   int // indentation of the content within the host file
+}
+
+// FindEmbeddedConfigs returns the Spring configuration embedded in YAML content,
+// at keys named like Spring configuration files or at the given paths
+func FindEmbeddedConfigs(content string, paths [][]string) []EmbeddedConfig {
+	doc := ParseYAML(content)
+	var configs []EmbeddedConfig
+	for _, entry := range doc.Entries {
+		if entry.Item {
+			continue
+		}
+		name := strings.ToLower(entry.Key)
+		named := springConfigPattern.MatchString(name)
+		if !named && !matchesAnyKeyPath(entry, paths) {
+			continue
+		}
+
+		literal := entry.IsBlockScalar() && entry.RawValue[0] == '|'
+		if !literal && (entry.RawValue != "" || len(entry.Children) == 0) {
+			continue
+		}
+		if len(configs) > 0 && isWithinEmbeddedConfig(entry.Line, configs[len(configs)-1]) {
+			continue
+		}
+
+		config := EmbeddedConfig{
+			Key:      strings.Join(entryKeys(entry), "/"),
+			Type:     core.YAML,
+			Kind:     core.SpringApplicationConfig,
+			Document: entry.Document,
+			Start:    entry.Line + 1,
+			End:      entry.End,
+			Indent:   embeddedIndent(doc.Lines, entry),
+		}
+		if literal && filepath.Ext(name) == ".properties" {
+			config.Type = core.Properties
+		}
+		if named {
+			config.Kind = ClassifyConfigFile(name)
+		}
+		configs = append(configs, config)
+	}
+	return configs
+}
+
+// isWithinEmbeddedConfig reports whether a line lies inside embedded content
+func isWithinEmbeddedConfig(line int, config EmbeddedConfig) bool {
+	return line >= config.Start && line < config.End
+}
+
+// embeddedIndent returns the indentation of the content below an entry: the
+// indentation indicator of a block scalar, or that of its first content line
+func embeddedIndent(lines []string, entry *YAMLEntry) int {
+	if entry.IsBlockScalar() {
+		if indicator, err := strconv.Atoi(strings.Trim(entry.RawValue[1:], "+-")); err == nil {
+			return entry.Indent + indicator
+		}
+	}
+	for _, line := range lines[entry.Line+1 : entry.End] {
+		if strings.TrimSpace(line) != "" && !isYAMLComment(line) {
+			return len(line) - len(strings.TrimLeft(line, " "))
+		}
+	}
+	return entry.Indent + 2
+}
+
+// EmbeddedConfigContent returns the embedded content without the indentation
+// it has in the host, ending with a newline like a file of its own
+func EmbeddedConfigContent(content string, config EmbeddedConfig) string {
+	lines := strings.Split(content, "\n")
+	embedded := make([]string, 0, config.End-config.Start)
+	for _, line := range lines[config.Start:config.End] {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent > config.Indent {
+			indent = config.Indent
+		}
+		embedded = append(embedded, line[indent:])
+	}
+	return strings.Join(embedded, "\n") + "\n"
+}
+
+// ReplaceEmbeddedConfig returns the host content with the embedded content
+// replaced, indenting every non-blank line as the content was
+func ReplaceEmbeddedConfig(content string, config EmbeddedConfig, embedded string) string {
+	lines := strings.Split(content, "\n")
+	indent := strings.Repeat(" ", config.Indent)
+
+	var replaced []string
+	if embedded = strings.TrimSuffix(embedded, "\n"); embedded != "" {
+		for _, line := range strings.Split(embedded, "\n") {
+			if strings.TrimSpace(line) == "" {
+				replaced = append(replaced, "")
+				continue
+			}
+			replaced = append(replaced, indent+line)
+		}
+	}
+
+	result := make([]string, 0, len(lines)-(config.End-config.Start)+len(replaced))
+	result = append(result, lines[:config.Start]...)
+	result = append(result, replaced...)
+	return strings.Join(append(result, lines[config.End:]...), "\n")
+}
+
+// ParseJSONPath parses a JSONPath naming a YAML key, such as
+// "$.app.config", "$['application.yml']" or "$.configs.*", into its keys,
+// where "*" matches any key
+func ParseJSONPath(path string) ([]string, error) {
+	rest := strings.TrimSpace(path)
+	if !strings.HasPrefix(rest, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+	rest = rest[1:]
+
+	var keys []string
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, `["`):
+			closing := strings.Index(rest[2:], string(rest[1])+"]")
+			if closing < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unterminated bracket", path)
+			}
+			keys = append(keys, rest[2:2+closing])
+			rest = rest[2+closing+2:]
+		case strings.HasPrefix(rest, "[*]"):
+			keys = append(keys, "*")
+			rest = rest[3:]
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty key", path)
+			}
+			keys = append(keys, rest[1:1+end])
+			rest = rest[1+end:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", path, rest[:1])
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("invalid JSONPath %q: no key", path)
+	}
+	return keys, nil
+}
+
+// matchesAnyKeyPath reports whether the keys leading to an entry match one of the parsed paths
+func matchesAnyKeyPath(entry *YAMLEntry, paths [][]string) bool {
+	keys := entryKeys(entry)
+	for _, path := range paths {
+		if len(path) != len(keys) {
+			continue
+		}
+		matched := true
+		for i, key := range path {
+			if key != "*" && key != keys[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// entryKeys returns the keys leading from the document root to an entry
+func entryKeys(entry *YAMLEntry) []string {
+	var keys []string
+	for e := entry; e != nil; e = e.Parent {
+		keys = append([]string{e.Key}, keys...)
+	}
+	return keys
+}
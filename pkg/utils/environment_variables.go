@@ -0,0 +1,406 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+var (
+	// environmentBlockPattern matches the key of a docker-compose "environment"
+	// or Kubernetes container "env" block, capturing the indentation of the key
+	environmentBlockPattern = regexp.MustCompile(`^(\s*(?:-\s+)?)(?:env|environment):\s*(?:#.*)?$`)
+
+	// kubernetesDataPattern matches the data of a ConfigMap or Secret, whose keys
+	// become environment variables through envFrom
+	kubernetesDataPattern = regexp.MustCompile(`^(?:data|stringData):\s*(?:#.*)?$`)
+
+	// kubernetesEnvKindPattern matches the kinds whose data keys may be environment variables
+	kubernetesEnvKindPattern = regexp.MustCompile(`^kind:\s*(?:ConfigMap|Secret)\s*(?:#.*)?$`)
+
+	// kubernetesNamePattern matches the "name" field of a Kubernetes env item
+	kubernetesNamePattern = regexp.MustCompile(`^(\s*(?:-\s+)?)name:[ \t]*`)
+)
+
+// EnvironmentVariable is the definition of an environment variable in a
+// Dockerfile, .env file, docker-compose file or Kubernetes manifest
+type EnvironmentVariable struct {
+	Name      string
+	Line      int // index of the line holding the name
+	NameStart int // offset of the name in Lines[Line]
+	NameEnd   int // offset just past the name in Lines[Line]
+	Start     int // first line of the definition
+	End       int // one past the last line of the definition
+	// Block is the line of the "env:", "environment:" or "data:" key the
+	// definition is nested under, or -1
+	Block int
+	// Shared is set when the lines of the definition define other variables
+	// too, as in a Dockerfile "ENV A=1 B=2"; DefinitionStart and DefinitionEnd
+	// then delimit "NAME=value" in Lines[Line]
+	Shared          bool
+	DefinitionStart int
+	DefinitionEnd   int
+}
+
+// FindEnvironmentVariables returns the environment variables defined by the
+// lines of a Dockerfile, a .env file, or a docker-compose file or Kubernetes
+// manifest, in the order they appear
+func FindEnvironmentVariables(lines []string, fileType core.FileType) []EnvironmentVariable {
+	var variables []EnvironmentVariable
+	switch fileType {
+	case core.Dockerfile:
+		variables = findDockerfileVariables(lines)
+	case core.DotEnv:
+		variables = findDotEnvVariables(lines)
+	case core.YAML:
+		variables = findYAMLEnvironmentVariables(lines)
+	}
+	sort.SliceStable(variables, func(i, j int) bool {
+		if variables[i].Line != variables[j].Line {
+			return variables[i].Line < variables[j].Line
+		}
+		return variables[i].NameStart < variables[j].NameStart
+	})
+	return variables
+}
+
+// dockerfileToken is a whitespace-separated argument of a Dockerfile instruction
+type dockerfileToken struct {
+	line, start, end int
+	text             string
+}
+
+// findDockerfileVariables returns the variables of the ENV instructions, in
+// both the "ENV NAME=value ..." and the legacy "ENV NAME value" form
+func findDockerfileVariables(lines []string) []EnvironmentVariable {
+	var variables []EnvironmentVariable
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// An instruction continues over lines ending with a backslash
+		end := i + 1
+		for line := i; line < len(lines) && strings.HasSuffix(strings.TrimRight(lines[line], " \t"), "\\"); line++ {
+			end = line + 2
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		tokens := dockerfileTokens(lines, i, end)
+		if len(tokens) > 1 && strings.EqualFold(tokens[0].text, "ENV") {
+			variables = append(variables, dockerfileEnvVariables(tokens[1:], i, end)...)
+		}
+		i = end - 1
+	}
+	return variables
+}
+
+// dockerfileEnvVariables returns the variables set by the arguments of an ENV instruction
+func dockerfileEnvVariables(arguments []dockerfileToken, start, end int) []EnvironmentVariable {
+	if !strings.Contains(arguments[0].text, "=") {
+		name := arguments[0]
+		return []EnvironmentVariable{{
+			Name: name.text, Line: name.line, NameStart: name.start, NameEnd: name.end,
+			Start: start, End: end, Block: -1,
+		}}
+	}
+
+	var variables []EnvironmentVariable
+	for _, argument := range arguments {
+		separator := strings.Index(argument.text, "=")
+		if separator <= 0 {
+			continue
+		}
+		variables = append(variables, EnvironmentVariable{
+			Name:            argument.text[:separator],
+			Line:            argument.line,
+			NameStart:       argument.start,
+			NameEnd:         argument.start + separator,
+			Start:           start,
+			End:             end,
+			Block:           -1,
+			DefinitionStart: argument.start,
+			DefinitionEnd:   argument.end,
+		})
+	}
+	if len(variables) > 1 {
+		for i := range variables {
+			variables[i].Shared = true
+		}
+	}
+	return variables
+}
+
+// dockerfileTokens splits the lines [start, end) of an instruction into
+// arguments, honouring quotes, backslash escapes and line continuations
+func dockerfileTokens(lines []string, start, end int) []dockerfileToken {
+	var tokens []dockerfileToken
+	for line := start; line < end; line++ {
+		text := lines[line]
+		if line > start && strings.HasPrefix(strings.TrimSpace(text), "#") {
+			continue
+		}
+		for i := 0; i < len(text); {
+			if text[i] == ' ' || text[i] == '\t' {
+				i++
+				continue
+			}
+			if text[i] == '\\' && strings.TrimSpace(text[i+1:]) == "" {
+				break
+			}
+
+			tokenStart := i
+			var quote byte
+			for i < len(text) && (quote != 0 || (text[i] != ' ' && text[i] != '\t')) {
+				switch {
+				case text[i] == '\\' && quote != '\'':
+					i++
+				case quote != 0 && text[i] == quote:
+					quote = 0
+				case quote == 0 && (text[i] == '"' || text[i] == '\''):
+					quote = text[i]
+				}
+				i++
+			}
+			if i > len(text) {
+				i = len(text)
+			}
+			tokens = append(tokens, dockerfileToken{line: line, start: tokenStart, end: i, text: text[tokenStart:i]})
+		}
+	}
+	return tokens
+}
+
+// findDotEnvVariables returns the "NAME=value" and "export NAME=value" lines of a .env file
+func findDotEnvVariables(lines []string) []EnvironmentVariable {
+	var variables []EnvironmentVariable
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		nameStart := len(line) - len(strings.TrimLeft(line, " \t"))
+		if strings.HasPrefix(line[nameStart:], "export ") {
+			nameStart = len(line) - len(strings.TrimLeft(line[nameStart+len("export "):], " \t"))
+		}
+		separator := strings.Index(line[nameStart:], "=")
+		if nameStart >= len(line) || line[nameStart] == '#' || separator <= 0 {
+			continue
+		}
+		name := strings.TrimRight(line[nameStart:nameStart+separator], " \t")
+		if strings.ContainsAny(name, " \t") {
+			continue
+		}
+
+		// A double-quoted value may span several lines
+		end := i + 1
+		value := strings.TrimSpace(line[nameStart+separator+1:])
+		if strings.HasPrefix(value, `"`) && !closesDoubleQuote(value[1:]) {
+			for end < len(lines) && !closesDoubleQuote(lines[end]) {
+				end++
+			}
+			if end < len(lines) {
+				end++
+			}
+		}
+
+		variables = append(variables, EnvironmentVariable{
+			Name: name, Line: i, NameStart: nameStart, NameEnd: nameStart + len(name),
+			Start: i, End: end, Block: -1,
+		})
+		i = end - 1
+	}
+	return variables
+}
+
+// closesDoubleQuote reports whether text contains an unescaped double quote
+func closesDoubleQuote(text string) bool {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return true
+		}
+	}
+	return false
+}
+
+// findYAMLEnvironmentVariables returns the variables of docker-compose
+// "environment" blocks, in mapping or list form, of Kubernetes container "env"
+// lists, and the keys of ConfigMaps and Secrets that are environment variable names
+func findYAMLEnvironmentVariables(lines []string) []EnvironmentVariable {
+	var variables []EnvironmentVariable
+	environmentKind := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if lineIndent(line) == 0 && (trimmed == "---" || strings.HasPrefix(trimmed, "--- ")) {
+			environmentKind = false
+			continue
+		}
+		if kubernetesEnvKindPattern.MatchString(line) {
+			environmentKind = true
+			continue
+		}
+
+		if match := environmentBlockPattern.FindStringSubmatch(line); match != nil {
+			variables = append(variables, environmentBlockVariables(lines, i, len(match[1]), false)...)
+		}
+		if kubernetesDataPattern.MatchString(line) && (environmentKind || documentDeclaresEnvironmentKind(lines, i)) {
+			variables = append(variables, environmentBlockVariables(lines, i, 0, true)...)
+		}
+	}
+	return variables
+}
+
+// documentDeclaresEnvironmentKind reports whether a "kind: ConfigMap" or
+// "kind: Secret" line follows the line within the same YAML document
+func documentDeclaresEnvironmentKind(lines []string, line int) bool {
+	for _, next := range lines[line+1:] {
+		trimmed := strings.TrimSpace(next)
+		if lineIndent(next) == 0 && (trimmed == "---" || strings.HasPrefix(trimmed, "--- ")) {
+			return false
+		}
+		if kubernetesEnvKindPattern.MatchString(next) {
+			return true
+		}
+	}
+	return false
+}
+
+// environmentBlockVariables returns the variables defined in the block below
+// the key on line block, whose key starts at column keyIndent. Data blocks only
+// yield keys written as environment variable names.
+func environmentBlockVariables(lines []string, block, keyIndent int, data bool) []EnvironmentVariable {
+	// The items of the block: lines deeper than the key, or sequence items at its column
+	itemIndent := -1
+	var items []int
+	end := block + 1
+	for j := block + 1; j < len(lines); j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := lineIndent(lines[j])
+		if indent < keyIndent || (indent == keyIndent && !strings.HasPrefix(trimmed, "-")) {
+			break
+		}
+		if itemIndent < 0 {
+			itemIndent = indent
+		}
+		if indent < itemIndent {
+			break
+		}
+		if indent == itemIndent {
+			items = append(items, j)
+		}
+		end = j + 1
+	}
+
+	var variables []EnvironmentVariable
+	for k, item := range items {
+		itemEnd := end
+		if k+1 < len(items) {
+			itemEnd = lastContentLine(lines, item, items[k+1]) + 1
+		}
+
+		var variable EnvironmentVariable
+		var ok bool
+		rest := lines[item][itemIndent:]
+		switch {
+		case data:
+			variable, ok = yamlKeyVariable(lines[item])
+			ok = ok && IsEnvironmentVariableName(variable.Name)
+		case strings.HasPrefix(rest, "- ") || rest == "-":
+			variable, ok = sequenceItemVariable(lines, item, itemEnd, itemIndent)
+		default:
+			variable, ok = yamlKeyVariable(lines[item])
+		}
+		if !ok {
+			continue
+		}
+		variable.Line = item + variable.Line
+		variable.Start, variable.End, variable.Block = item, itemEnd, block
+		variables = append(variables, variable)
+	}
+	return variables
+}
+
+// yamlKeyVariable returns the variable named by the key of a mapping line,
+// with Line relative to that line
+func yamlKeyVariable(line string) (EnvironmentVariable, bool) {
+	entry := parseYAMLLine(line)
+	if entry == nil {
+		return EnvironmentVariable{}, false
+	}
+	start, end := entry.KeyStart, entry.KeyEnd
+	if line[start] == '"' || line[start] == '\'' {
+		start, end = start+1, end-1
+	}
+	return EnvironmentVariable{Name: entry.Key, NameStart: start, NameEnd: end}, true
+}
+
+// sequenceItemVariable returns the variable of a docker-compose "- NAME=value"
+// item or a Kubernetes "- name: NAME" item, with Line relative to the first
+// line of the item
+func sequenceItemVariable(lines []string, item, itemEnd, dashIndent int) (EnvironmentVariable, bool) {
+	line := lines[item]
+	valueStart := dashIndent + 1
+	for valueStart < len(line) && (line[valueStart] == ' ' || line[valueStart] == '\t') {
+		valueStart++
+	}
+
+	// A mapping item is a Kubernetes EnvVar, whose name may follow its value
+	if entry := parseYAMLLine(strings.Repeat(" ", valueStart) + line[valueStart:]); entry != nil {
+		for j := item; j < itemEnd; j++ {
+			match := kubernetesNamePattern.FindStringIndex(lines[j])
+			if match == nil || (j > item && lineIndent(lines[j]) != valueStart) {
+				continue
+			}
+			raw := stripYAMLComment(lines[j][match[1]:])
+			start, end := match[1], match[1]+len(raw)
+			if len(raw) >= 2 && (raw[0] == '"' || raw[0] == '\'') {
+				start, end = start+1, end-1
+			}
+			if start >= end {
+				return EnvironmentVariable{}, false
+			}
+			return EnvironmentVariable{Name: lines[j][start:end], Line: j - item, NameStart: start, NameEnd: end}, true
+		}
+		return EnvironmentVariable{}, false
+	}
+
+	raw := stripYAMLComment(line[valueStart:])
+	start := valueStart
+	if len(raw) >= 2 && (raw[0] == '"' || raw[0] == '\'') {
+		start++
+		raw = raw[1 : len(raw)-1]
+	}
+	name := raw
+	if separator := strings.Index(raw, "="); separator >= 0 {
+		name = raw[:separator]
+	}
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return EnvironmentVariable{}, false
+	}
+	return EnvironmentVariable{Name: name, NameStart: start, NameEnd: start + len(name)}, true
+}
+
+// lastContentLine returns the last line in [from, to) that is neither blank nor a comment
+func lastContentLine(lines []string, from, to int) int {
+	last := from
+	for j := from; j < to; j++ {
+		trimmed := strings.TrimSpace(lines[j])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			last = j
+		}
+	}
+	return last
+}
+
+// lineIndent returns the number of leading spaces of a line
+func lineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+func TestEnvironmentVariableNames(t *testing.T) {
+	tests := []struct {
+		key  string
+		name string
+		// equal tells whether the name binds to the key; bracketed map keys
+		// holding dots cannot be written as environment variables
+		equal bool
+	}{
+		{"server.port", "SERVER_PORT", true},
+		{"spring.datasource.driver-class-name", "SPRING_DATASOURCE_DRIVERCLASSNAME", true},
+		{"spring.datasource.driverClassName", "SPRING_DATASOURCE_DRIVERCLASSNAME", true},
+		{"spring.datasource.driver_class_name", "SPRING_DATASOURCE_DRIVERCLASSNAME", true},
+		{"my.list[0]", "MY_LIST_0", true},
+		{"my.list[0].name", "MY_LIST_0_NAME", true},
+		{"my.list.0.name", "MY_LIST_0_NAME", true},
+		{"my.matrix[0][1]", "MY_MATRIX_0_1", true},
+		{"logging.level[com.example]", "LOGGING_LEVEL_COM_EXAMPLE", false},
+		{"SERVER_PORT", "SERVER_PORT", true},
+		{"SPRING__DATASOURCE_URL", "SPRING__DATASOURCE_URL", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if actual := ToEnvironmentVariableName(tt.key); actual != tt.name {
+				t.Errorf("ToEnvironmentVariableName(%q) = %q, want %q", tt.key, actual, tt.name)
+			}
+			if actual := EqualKeys(tt.name, tt.key); actual != tt.equal {
+				t.Errorf("EqualKeys(%q, %q) = %v, want %v", tt.name, tt.key, actual, tt.equal)
+			}
+		})
+	}
+}
+
+func TestEnvironmentVariableKeyElements(t *testing.T) {
+	tests := []struct {
+		name     string
+		isEnv    bool
+		elements []string
+	}{
+		{"SERVER_PORT", true, []string{"server", "port"}},
+		{"MY_LIST_0_NAME", true, []string{"my", "list", "0", "name"}},
+		{"SPRING__DATASOURCE_URL", true, []string{"spring", "datasource", "url"}},
+		{"APP_V2_URL", true, []string{"app", "v2", "url"}},
+		{"A1_2", true, []string{"a1", "2"}},
+		{"PATH", false, []string{"path"}},
+		{"Server_Port", false, []string{"serverport"}},
+		{"_SERVER_PORT", false, []string{"serverport"}},
+		{"SERVER_PORT_", false, []string{"serverport"}},
+		{"1_SERVER", false, []string{"1server"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := IsEnvironmentVariableName(tt.name); actual != tt.isEnv {
+				t.Errorf("IsEnvironmentVariableName(%q) = %v, want %v", tt.name, actual, tt.isEnv)
+			}
+			if actual := PropertyKeyElements(tt.name); !reflect.DeepEqual(actual, tt.elements) {
+				t.Errorf("PropertyKeyElements(%q) = %q, want %q", tt.name, actual, tt.elements)
+			}
+		})
+	}
+
+	// The index form and the double underscore name the same property as the dotted key
+	for _, pair := range [][2]string{
+		{"MY_LIST_0_NAME", "my.list[0].name"},
+		{"SPRING__DATASOURCE_URL", "spring.datasource.url"},
+	} {
+		if !EqualKeys(pair[0], pair[1]) {
+			t.Errorf("EqualKeys(%q, %q) = false", pair[0], pair[1])
+		}
+	}
+	if EqualKeys("MY_LIST_0_NAME", "my.list[1].name") {
+		t.Error("EqualKeys(MY_LIST_0_NAME, my.list[1].name) = true")
+	}
+}
+
+// environmentVariableSummaries describes variables as NAME@line:start-end
+func environmentVariableSummaries(lines []string, variables []EnvironmentVariable) []string {
+	var summaries []string
+	for _, variable := range variables {
+		if name := lines[variable.Line][variable.NameStart:variable.NameEnd]; name != variable.Name {
+			summaries = append(summaries, fmt.Sprintf("%s has the range of %s", variable.Name, name))
+			continue
+		}
+		summaries = append(summaries, fmt.Sprintf("%s@%d:%d-%d", variable.Name, variable.Line, variable.Start, variable.End))
+	}
+	return summaries
+}
+
+func TestFindEnvironmentVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileType core.FileType
+		content  string
+		expected []string
+	}{
+		{
+			name:     "Dockerfile",
+			fileType: core.Dockerfile,
+			content:  "FROM java\nENV SERVER_PORT=8080 SPRING_PROFILES_ACTIVE=\"a b\"\nENV LEGACY value\nENV MULTI=1 \\\n    OTHER=2\n",
+			expected: []string{"SERVER_PORT@1:1-2", "SPRING_PROFILES_ACTIVE@1:1-2", "LEGACY@2:2-3", "MULTI@3:3-5", "OTHER@4:3-5"},
+		},
+		{
+			name:     ".env",
+			fileType: core.DotEnv,
+			content:  "# comment\nSERVER_PORT=8080\nexport MY_LIST_0_NAME=a\nMULTI=\"one\ntwo\"\nnot a variable\n",
+			expected: []string{"SERVER_PORT@1:1-2", "MY_LIST_0_NAME@2:2-3", "MULTI@3:3-5"},
+		},
+		{
+			name:     "docker-compose",
+			fileType: core.YAML,
+			content:  "services:\n  app:\n    environment:\n      SERVER_PORT: 8080\n      \"QUOTED_NAME\": x\n  other:\n    environment:\n      - SPRING__DATASOURCE_URL=jdbc\n      - PLAIN\n",
+			expected: []string{"SERVER_PORT@3:3-4", "QUOTED_NAME@4:4-5", "SPRING__DATASOURCE_URL@7:7-8", "PLAIN@8:8-9"},
+		},
+		{
+			name:     "Kubernetes",
+			fileType: core.YAML,
+			content:  "containers:\n  - env:\n      - name: SERVER_PORT\n        value: \"8080\"\n      - value: x\n        name: 'MY_LIST_0'\n---\nkind: ConfigMap\ndata:\n  SPRING_PROFILES_ACTIVE: prod\n  application.yml: |\n    a: 1\n",
+			expected: []string{"SERVER_PORT@2:2-4", "MY_LIST_0@5:4-6", "SPRING_PROFILES_ACTIVE@9:9-10"},
+		},
+		{
+			name:     "data outside ConfigMaps and Secrets",
+			fileType: core.YAML,
+			content:  "kind: Deployment\ndata:\n  SERVER_PORT: 8080\n",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(tt.content, "\n")
+			actual := environmentVariableSummaries(lines, FindEnvironmentVariables(lines, tt.fileType))
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("FindEnvironmentVariables() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -35,20 +35,15 @@ func globToRegex(glob string) string {
 	return "^" + escaped + "$"
 }
 
-// LoadSourceFile loads a source file from disk
+// LoadSourceFile loads a source file from disk using the default parser registry.
+// Files that no parser accepts (such as binary files) yield core.ErrUnsupportedFile.
 func LoadSourceFile(filePath string) (core.SourceFile, error) {
 	content, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
-	fileType := DetermineFileType(filePath)
-
-	return &core.SpringConfigFile{
-		Path:    filePath,
-		Content: string(content),
-		Type:    fileType,
-	}, nil
+	return DefaultParserRegistry.Parse(filePath, content)
 }
 
 // SaveSourceFile saves a source file to disk
@@ -59,8 +54,14 @@ func SaveSourceFile(sourceFile core.SourceFile, outputPath string) error {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
+	// Encode content with the charset, BOM and line ending it was read with
+	content, err := core.EncodeContent(sourceFile.GetContent(), sourceFile.GetEncoding(), sourceFile.GetType())
+	if err != nil {
+		return fmt.Errorf("failed to encode file %s: %w", outputPath, err)
+	}
+
 	// Write file content
-	err := ioutil.WriteFile(outputPath, []byte(sourceFile.GetContent()), 0644)
+	err = ioutil.WriteFile(outputPath, content, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to write file %s: %w", outputPath, err)
 	}
@@ -68,24 +69,11 @@ func SaveSourceFile(sourceFile core.SourceFile, outputPath string) error {
 	return nil
 }
 
-// DetermineFileType determines the file type based on extension
+// DetermineFileType determines the file type using the default parser registry.
+// Unrecognised files are reported as core.PlainText.
 func DetermineFileType(filePath string) core.FileType {
-	ext := strings.ToLower(filepath.Ext(filePath))
-
-	switch ext {
-	case ".properties":
-		return core.Properties
-	case ".yml", ".yaml":
-		return core.YAML
-	case ".java":
-		return core.Java
-	default:
-		// Check if it's a properties file without extension
-		if strings.Contains(filepath.Base(filePath), "application") {
-			return core.Properties
-		}
-		return core.Properties // Default fallback
-	}
+	fileType, _ := DefaultParserRegistry.Detect(filePath)
+	return fileType
 }
 
 // FindSpringConfigFiles finds Spring configuration files in a directory
@@ -153,28 +141,13 @@ func WriteFileLines(filePath string, lines []string) error {
 
 // IsSpringConfigFile checks if a file is likely a Spring configuration file
 func IsSpringConfigFile(filePath string) bool {
-	fileName := strings.ToLower(filepath.Base(filePath))
-
-	// Check for common Spring configuration file patterns
-	patterns := []string{
-		"application.properties",
-		"application.yml",
-		"application.yaml",
-		"application-*.properties",
-		"application-*.yml",
-		"application-*.yaml",
-		"bootstrap.properties",
-		"bootstrap.yml",
-		"bootstrap.yaml",
-	}
-
-	for _, pattern := range patterns {
-		if matched, _ := MatchGlob(fileName, pattern); matched {
-			return true
-		}
-	}
+	return ClassifyConfigFile(filePath).IsSpringConfig()
+}
 
-	return false
+// FileExists reports whether a regular file exists at the path
+func FileExists(filePath string) bool {
+	info, err := os.Stat(filePath)
+	return err == nil && !info.IsDir()
 }
 
 // BackupFile creates a backup of a file
@@ -193,3 +166,15 @@ func BackupFile(filePath string) error {
 
 	return nil
 }
+
+// NewSourceFile creates an in-memory source file for a path that may not exist yet,
+// determining its file type and config kind from the path
+func NewSourceFile(filePath, content string, encoding core.Encoding) core.SourceFile {
+	return &core.SpringConfigFile{
+		Path:     filePath,
+		Content:  content,
+		Type:     DetermineFileType(filePath),
+		Kind:     ClassifyConfigFile(filePath),
+		Encoding: encoding,
+	}
+}
@@ -0,0 +1,363 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// objectValuedProperties are the MapValuedProperties whose map values are nested
+// objects; their keys are written as nested YAML mappings like any other key
+var objectValuedProperties = []string{
+	"spring.security.oauth2.client.registration",
+	"spring.security.oauth2.client.provider",
+	"spring.cloud.stream.bindings",
+	"info",
+}
+
+// IsPropertiesDocumentSeparator reports whether a line is the "#---" or "!---"
+// separator of a multi-document properties file
+func IsPropertiesDocumentSeparator(line string) bool {
+	trimmed := strings.TrimRight(line, " \t")
+	return trimmed == "#---" || trimmed == "!---"
+}
+
+// SplitPropertiesDocuments splits properties content into its documents
+func SplitPropertiesDocuments(content string) []string {
+	lines := strings.Split(content, "\n")
+	var documents []string
+	start := 0
+	for i, line := range lines {
+		if IsPropertiesDocumentSeparator(line) {
+			documents = append(documents, strings.Join(lines[start:i], "\n"))
+			start = i + 1
+		}
+	}
+	return append(documents, strings.Join(lines[start:], "\n"))
+}
+
+// ConvertPropertiesToYAML converts properties content into equivalent YAML.
+// Documents become YAML documents and comments are kept above the property
+// they precede. Properties that YAML cannot represent, such as a key holding
+// both a value and nested keys, are reported as an error.
+func ConvertPropertiesToYAML(content string) (string, error) {
+	return ConvertPropertiesToYAMLWithIndent(content, 2)
+}
+
+// ConvertPropertiesToYAMLWithIndent converts properties content into YAML
+// that nests mappings by indentUnit spaces, e.g. to match an existing file
+func ConvertPropertiesToYAMLWithIndent(content string, indentUnit int) (string, error) {
+	documents := SplitPropertiesDocuments(content)
+	converted := make([]string, 0, len(documents))
+	for i, document := range documents {
+		yaml, err := propertiesDocumentToYAML(document, indentUnit)
+		if err != nil {
+			if len(documents) > 1 {
+				return "", fmt.Errorf("document %d: %w", i+1, err)
+			}
+			return "", err
+		}
+		converted = append(converted, yaml)
+	}
+	return strings.Join(converted, "---\n"), nil
+}
+
+// ConvertYAMLToProperties converts YAML content into equivalent properties.
+// Documents are separated by "#---" and comments are kept above the property
+// they precede; trailing comments move to the line above their property.
+func ConvertYAMLToProperties(content string) (string, error) {
+	doc := ParseYAML(content)
+	converted := make([]string, 0, doc.DocumentCount())
+	for document := 0; document < doc.DocumentCount(); document++ {
+		properties, err := doc.FlattenDocument(document)
+		if err != nil {
+			return "", err
+		}
+
+		start, end := doc.DocumentStarts[document], doc.DocumentEnd(document)
+		var lines []string
+		next := start
+		for _, property := range properties {
+			for ; next < property.Line; next++ {
+				if isYAMLComment(doc.Lines[next]) {
+					lines = append(lines, strings.TrimSpace(doc.Lines[next]))
+				}
+			}
+			if next < property.End {
+				next = property.End
+			}
+			if property.Comment != "" {
+				lines = append(lines, property.Comment)
+			}
+			lines = append(lines, EscapePropertiesKey(property.Key)+"="+EscapePropertiesValue(property.Value))
+		}
+		for ; next < end; next++ {
+			if isYAMLComment(doc.Lines[next]) {
+				lines = append(lines, strings.TrimSpace(doc.Lines[next]))
+			}
+		}
+
+		converted = append(converted, strings.Join(lines, "\n"))
+	}
+
+	result := strings.Join(converted, "\n#---\n")
+	if result == "" {
+		return "", nil
+	}
+	return result + "\n", nil
+}
+
+// propertiesDocumentToYAML converts a single properties document
+func propertiesDocumentToYAML(content string, indentUnit int) (string, error) {
+	doc := ParseProperties(content)
+	root := &yamlNode{}
+
+	previousEnd := 0
+	for _, entry := range doc.Entries {
+		node, err := root.descend(entry.Key, tokenizePropertyKey(entry.Key))
+		if err != nil {
+			return "", err
+		}
+		node.comments = append(node.comments, propertiesComments(doc.Lines[previousEnd:entry.Line])...)
+		node.value = UnescapePropertiesValue(entry.Value)
+		node.hasValue = true
+		previousEnd = entry.End
+	}
+
+	lines, err := root.render("", 0, indentUnit)
+	if err != nil {
+		return "", err
+	}
+	lines = append(lines, propertiesComments(doc.Lines[previousEnd:])...)
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// propertiesComments returns the comment lines among lines in YAML syntax
+func propertiesComments(lines []string) []string {
+	var comments []string
+	for _, line := range lines {
+		if IsPropertiesComment(line) {
+			comment := strings.TrimLeft(line, " \t\f")
+			comments = append(comments, "#"+comment[1:])
+		}
+	}
+	return comments
+}
+
+// keyToken is one step of a property key: a mapping key or a list index
+type keyToken struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// tokenizePropertyKey splits a key such as "a.b[0].c[com.example]" into its
+// mapping keys and list indexes. The keys of scalar map-valued properties such
+// as "logging.level" are kept whole, e.g. "org.springframework.web".
+func tokenizePropertyKey(key string) []keyToken {
+	var tokens []keyToken
+	path := ""
+	segments := SplitPropertyKey(key)
+	for i, segment := range segments {
+		if path != "" && isScalarMapValuedProperty(path) {
+			rest := strings.Join(segments[i:], ".")
+			if !strings.Contains(rest, "[") {
+				return append(tokens, keyToken{key: rest})
+			}
+		}
+
+		name := segment
+		brackets := ""
+		if idx := strings.Index(segment, "["); idx >= 0 {
+			name, brackets = segment[:idx], segment[idx:]
+		}
+		if name != "" {
+			tokens = append(tokens, keyToken{key: name})
+			path = JoinYAMLPath(path, name)
+		}
+
+		for brackets != "" {
+			closing := strings.Index(brackets, "]")
+			if closing < 0 {
+				// Unbalanced bracket: keep the rest as part of the key
+				tokens = append(tokens, keyToken{key: brackets})
+				break
+			}
+			inner := brackets[1:closing]
+			if index, err := strconv.Atoi(inner); err == nil && index >= 0 {
+				tokens = append(tokens, keyToken{index: index, isIndex: true})
+			} else {
+				tokens = append(tokens, keyToken{key: brackets[:closing+1]})
+			}
+			path += brackets[:closing+1]
+			brackets = brackets[closing+1:]
+		}
+	}
+	return tokens
+}
+
+// SplitYAMLPath splits a property key into the YAML mapping keys it nests
+// under, e.g. "a.b[com.example]" into "a", "b" and "[com.example]". Keys of
+// scalar map-valued properties are kept whole and list indexes stay attached
+// to the key they follow.
+func SplitYAMLPath(key string) []string {
+	var keys []string
+	for _, token := range tokenizePropertyKey(key) {
+		if token.isIndex {
+			index := "[" + strconv.Itoa(token.index) + "]"
+			if len(keys) > 0 {
+				keys[len(keys)-1] += index
+			} else {
+				keys = append(keys, index)
+			}
+			continue
+		}
+		keys = append(keys, token.key)
+	}
+	return keys
+}
+
+// isScalarMapValuedProperty reports whether key is a map-valued property whose
+// map values are scalars
+func isScalarMapValuedProperty(key string) bool {
+	if !IsMapValuedProperty(key) {
+		return false
+	}
+	for _, property := range objectValuedProperties {
+		if EqualKeys(key, property) {
+			return false
+		}
+	}
+	return true
+}
+
+// yamlNode is a node of the YAML tree built from properties
+type yamlNode struct {
+	comments []string
+	value    string
+	hasValue bool
+	keys     []string
+	fields   map[string]*yamlNode
+	items    map[int]*yamlNode
+}
+
+// descend returns the node for the tokens, creating the missing nodes
+func (n *yamlNode) descend(key string, tokens []keyToken) (*yamlNode, error) {
+	node := n
+	for _, token := range tokens {
+		if token.isIndex {
+			if len(node.keys) > 0 {
+				return nil, fmt.Errorf("%s mixes list indexes and nested keys", key)
+			}
+			if node.items == nil {
+				node.items = make(map[int]*yamlNode)
+			}
+			child, ok := node.items[token.index]
+			if !ok {
+				child = &yamlNode{}
+				node.items[token.index] = child
+			}
+			node = child
+			continue
+		}
+
+		if len(node.items) > 0 {
+			return nil, fmt.Errorf("%s mixes list indexes and nested keys", key)
+		}
+		if node.fields == nil {
+			node.fields = make(map[string]*yamlNode)
+		}
+		child, ok := node.fields[token.key]
+		if !ok {
+			child = &yamlNode{}
+			node.fields[token.key] = child
+			node.keys = append(node.keys, token.key)
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// render returns the YAML lines of the node's children at the given indent,
+// nesting mappings by unit spaces
+func (n *yamlNode) render(path string, indent, unit int) ([]string, error) {
+	prefix := strings.Repeat(" ", indent)
+	var lines []string
+
+	if len(n.items) > 0 {
+		indexes := make([]int, 0, len(n.items))
+		for index := range n.items {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		for i, index := range indexes {
+			if index != i {
+				return nil, fmt.Errorf("%s[%d] is missing, YAML lists cannot have gaps", path, i)
+			}
+			item := n.items[index]
+			itemPath := fmt.Sprintf("%s[%d]", path, index)
+			lines = append(lines, indentComments(item.comments, prefix)...)
+
+			if item.hasValue {
+				if len(item.keys) > 0 || len(item.items) > 0 {
+					return nil, fmt.Errorf("%s has both a value and nested properties", itemPath)
+				}
+				lines = append(lines, prefix+"- "+FormatYAMLValue(item.value))
+				continue
+			}
+
+			// The keys of a mapping item align with the text after the dash
+			children, err := item.render(itemPath, indent+2, unit)
+			if err != nil {
+				return nil, err
+			}
+			// The first key of a mapping item shares the line of the dash, and
+			// its comments move up to the indent of the dash
+			for j, line := range children {
+				if isYAMLComment(line) {
+					children[j] = prefix + strings.TrimSpace(line)
+					continue
+				}
+				children[j] = prefix + "- " + line[indent+2:]
+				break
+			}
+			lines = append(lines, children...)
+		}
+		return lines, nil
+	}
+
+	for _, key := range n.keys {
+		child := n.fields[key]
+		childPath := JoinYAMLPath(path, key)
+		lines = append(lines, indentComments(child.comments, prefix)...)
+
+		if child.hasValue {
+			if len(child.keys) > 0 || len(child.items) > 0 {
+				return nil, fmt.Errorf("%s has both a value and nested properties", childPath)
+			}
+			lines = append(lines, prefix+FormatYAMLKey(key)+": "+FormatYAMLValue(child.value))
+			continue
+		}
+
+		children, err := child.render(childPath, indent+unit, unit)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, prefix+FormatYAMLKey(key)+":")
+		lines = append(lines, children...)
+	}
+	return lines, nil
+}
+
+// indentComments places comment lines at the given indent
+func indentComments(comments []string, prefix string) []string {
+	indented := make([]string, len(comments))
+	for i, comment := range comments {
+		indented[i] = prefix + comment
+	}
+	return indented
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropertiesYAMLRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties string
+		yaml       string
+	}{
+		{
+			name:       "nested keys and comments",
+			properties: "# server\nserver.port=8080\nserver.address=localhost\n# app\nspring.application.name=app\n# end\n",
+			yaml:       "server:\n  # server\n  port: 8080\n  address: localhost\nspring:\n  application:\n    # app\n    name: app\n# end\n",
+		},
+		{
+			name:       "indexed lists",
+			properties: "list[0]=a\nlist[1].name=b\nlist[1].port=1\n",
+			yaml:       "list:\n  - a\n  - name: b\n    port: 1\n",
+		},
+		{
+			name:       "values needing quotes",
+			properties: "a=yes\nb=0x1F\nc=a: b\nd=\\ padded\ne=it's\nf=\n",
+			yaml:       "a: 'yes'\nb: '0x1F'\nc: 'a: b'\nd: ' padded'\ne: it's\nf: ''\n",
+		},
+		{
+			name:       "escapes",
+			properties: "a=tab\\there\nb=C:\\\\path\nc=line\\nbreak\n",
+			yaml:       "a: \"tab\\there\"\nb: C:\\path\nc: \"line\\nbreak\"\n",
+		},
+		{
+			name:       "multi-document",
+			properties: "a=1\n#---\nspring.config.activate.on-profile=dev\na=2\n",
+			yaml:       "a: 1\n---\nspring:\n  config:\n    activate:\n      on-profile: dev\na: 2\n",
+		},
+		{
+			name:       "empty",
+			properties: "",
+			yaml:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yaml, err := ConvertPropertiesToYAML(tt.properties)
+			if err != nil {
+				t.Fatalf("ConvertPropertiesToYAML failed: %v", err)
+			}
+			if yaml != tt.yaml {
+				t.Errorf("ConvertPropertiesToYAML = %q, want %q", yaml, tt.yaml)
+			}
+			properties, err := ConvertYAMLToProperties(tt.yaml)
+			if err != nil {
+				t.Fatalf("ConvertYAMLToProperties failed: %v", err)
+			}
+			if properties != tt.properties {
+				t.Errorf("ConvertYAMLToProperties = %q, want %q", properties, tt.properties)
+			}
+		})
+	}
+}
+
+func TestConvertYAMLToProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected string
+	}{
+		{
+			name:     "trailing comments move above their property",
+			yaml:     "server:\n  port: 8080 # http\n",
+			expected: "# http\nserver.port=8080\n",
+		},
+		{
+			name:     "flow collections",
+			yaml:     "include: [health, info]\nmap: {a: 1}\n",
+			expected: "include[0]=health\ninclude[1]=info\nmap.a=1\n",
+		},
+		{
+			name:     "block scalars",
+			yaml:     "script: |\n  one\n  two\nnext: 1\n",
+			expected: "script=one\\ntwo\\n\nnext=1\n",
+		},
+		{
+			name:     "no trailing newline",
+			yaml:     "a:\n  b: 1",
+			expected: "a.b=1\n",
+		},
+		{
+			name:     "document markers",
+			yaml:     "---\na: 1\n...\n---\nb: 2\n",
+			expected: "a=1\n#---\nb=2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ConvertYAMLToProperties(tt.yaml)
+			if err != nil {
+				t.Fatalf("ConvertYAMLToProperties failed: %v", err)
+			}
+			if actual != tt.expected {
+				t.Errorf("ConvertYAMLToProperties = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertPropertiesToYAMLConflicts(t *testing.T) {
+	_, err := ConvertPropertiesToYAML("a=1\n#---\nb=1\nb.c=2\n")
+	if err == nil {
+		t.Fatal("expected an error for a key holding both a value and nested keys")
+	}
+}
+
+func TestSplitPropertiesDocuments(t *testing.T) {
+	actual := SplitPropertiesDocuments("a=1\n#---\nb=2\n!--- \nc=3")
+	expected := []string{"a=1", "b=2", "c=3"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("SplitPropertiesDocuments = %q, want %q", actual, expected)
+	}
+}
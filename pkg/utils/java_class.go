@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+// Access flags of Java class files
+const (
+	accEnum = 0x4000
+)
+
+// errClassFormat is returned for truncated or malformed class files
+var errClassFormat = errors.New("malformed class file")
+
+// LoadEnumConstants reads the constants of the enum types of the metadata
+// properties from the class files of jar files and directories, such as the
+// dependencies the metadata was loaded from, and records them in
+// metadata.Enums. Types whose class is not found are left out, and classes and
+// nested jars that cannot be read are skipped with a warning.
+func LoadEnumConstants(metadata *ConfigurationMetadata, paths []string, logger core.Logger) error {
+	wanted := enumCandidateClasses(metadata)
+	if len(wanted) == 0 {
+		return nil
+	}
+	if metadata.Enums == nil {
+		metadata.Enums = make(map[string][]string)
+	}
+
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return fmt.Errorf("failed to read classes from %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			if isArchiveFile(root) {
+				if err := loadEnumArchiveFile(metadata, root, wanted, logger); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		err = filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			switch {
+			case info.IsDir():
+				return nil
+			case isArchiveFile(filePath):
+				return loadEnumArchiveFile(metadata, filePath, wanted, logger)
+			case strings.HasSuffix(filePath, ".class"):
+				rel, err := filepath.Rel(root, filePath)
+				if err != nil {
+					return nil
+				}
+				javaType, ok := wantedClass(wanted, filepath.ToSlash(rel))
+				if !ok {
+					return nil
+				}
+				content, err := ioutil.ReadFile(filePath)
+				if err != nil {
+					return fmt.Errorf("failed to read file %s: %w", filePath, err)
+				}
+				addEnumConstants(metadata, javaType, filePath, content, logger)
+				return nil
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to walk %s: %w", root, err)
+		}
+	}
+	return nil
+}
+
+// enumCandidateClasses returns the class file names of the types of the
+// metadata properties that may be enums, by the type each names
+func enumCandidateClasses(metadata *ConfigurationMetadata) map[string]string {
+	wanted := make(map[string]string)
+	var add func(javaType string)
+	add = func(javaType string) {
+		raw, args := ParseJavaType(javaType)
+		for _, arg := range args {
+			add(arg)
+		}
+		raw = strings.TrimSuffix(raw, "[]")
+		if raw == "" || isValueType(raw) || isCollectionType(raw) || isMapType(raw) || strings.HasPrefix(raw, "java.") {
+			return
+		}
+		wanted[strings.ReplaceAll(raw, ".", "/")+".class"] = raw
+	}
+	for _, property := range metadata.Properties {
+		add(property.Type)
+	}
+	return wanted
+}
+
+// wantedClass returns the type of a class file, given by its path inside a jar
+// or directory, when it is one of the wanted classes. Classes of executable
+// jars and paths below a directory holding several class trees match too.
+func wantedClass(wanted map[string]string, name string) (string, bool) {
+	name = strings.TrimPrefix(name, "BOOT-INF/classes/")
+	for {
+		if javaType, ok := wanted[name]; ok {
+			return javaType, true
+		}
+		slash := strings.IndexByte(name, '/')
+		if slash < 0 {
+			return "", false
+		}
+		name = name[slash+1:]
+	}
+}
+
+// loadEnumArchiveFile reads the wanted enum classes of a jar file
+func loadEnumArchiveFile(metadata *ConfigurationMetadata, filePath string, wanted map[string]string, logger core.Logger) error {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	loadEnumArchive(metadata, filePath, content, wanted, logger)
+	return nil
+}
+
+// loadEnumArchive reads the wanted enum classes of a jar and of the jars it
+// contains, skipping the entries that cannot be read
+func loadEnumArchive(metadata *ConfigurationMetadata, name string, content []byte, wanted map[string]string, logger core.Logger) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		logger.Warn("Skipping enum classes of %s: %v", name, err)
+		return
+	}
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		javaType, isClass := "", false
+		if strings.HasSuffix(file.Name, ".class") {
+			javaType, isClass = wantedClass(wanted, file.Name)
+		}
+		if !isClass && !isArchiveFile(file.Name) {
+			continue
+		}
+		entryName := name + "!/" + file.Name
+		entryContent, err := readArchiveEntry(file)
+		if err != nil {
+			logger.Warn("Skipping %s: %v", entryName, err)
+			continue
+		}
+		if isClass {
+			addEnumConstants(metadata, javaType, entryName, entryContent, logger)
+		} else {
+			loadEnumArchive(metadata, entryName, entryContent, wanted, logger)
+		}
+	}
+}
+
+// addEnumConstants records the constants of a class file when it is an enum;
+// the first class found for a type wins
+func addEnumConstants(metadata *ConfigurationMetadata, javaType, name string, content []byte, logger core.Logger) {
+	if _, ok := metadata.Enums[javaType]; ok {
+		return
+	}
+	constants, isEnum, err := readEnumConstants(content)
+	if err != nil {
+		logger.Warn("Skipping class %s: %v", name, err)
+		return
+	}
+	if isEnum {
+		metadata.Enums[javaType] = constants
+	}
+}
+
+// readEnumConstants returns the constants of the enum a class file declares,
+// in declaration order, or false when the class is not an enum
+func readEnumConstants(content []byte) ([]string, bool, error) {
+	r := &classReader{data: content}
+	if r.u4() != 0xCAFEBABE {
+		return nil, false, errClassFormat
+	}
+	r.skip(4) // minor and major version
+
+	// Only UTF-8 entries are kept, for the names of fields
+	count := int(r.u2())
+	utf8 := make(map[int]string)
+	for i := 1; i < count && r.err == nil; i++ {
+		switch tag := r.u1(); tag {
+		case 1: // Utf8
+			length := int(r.u2())
+			utf8[i] = string(r.bytes(length))
+		case 7, 8, 16, 19, 20: // Class, String, MethodType, Module, Package
+			r.skip(2)
+		case 15: // MethodHandle
+			r.skip(3)
+		case 3, 4, 9, 10, 11, 12, 17, 18: // Integer, Float, refs, NameAndType, Dynamic, InvokeDynamic
+			r.skip(4)
+		case 5, 6: // Long and Double take two entries
+			r.skip(8)
+			i++
+		default:
+			return nil, false, fmt.Errorf("unknown constant pool tag %d: %w", tag, errClassFormat)
+		}
+	}
+
+	accessFlags := r.u2()
+	r.skip(4) // this and super class
+	r.skip(2 * int(r.u2()))
+	if r.err != nil {
+		return nil, false, r.err
+	}
+	if accessFlags&accEnum == 0 {
+		return nil, false, nil
+	}
+
+	var constants []string
+	fields := int(r.u2())
+	for i := 0; i < fields && r.err == nil; i++ {
+		flags, nameIndex := r.u2(), int(r.u2())
+		r.skip(2) // descriptor
+		attributes := int(r.u2())
+		for j := 0; j < attributes && r.err == nil; j++ {
+			r.skip(2)
+			r.skip(int(r.u4()))
+		}
+		if flags&accEnum != 0 {
+			constants = append(constants, utf8[nameIndex])
+		}
+	}
+	if r.err != nil {
+		return nil, false, r.err
+	}
+	return constants, true, nil
+}
+
+// classReader reads the big-endian values of a class file, recording the
+// first read past its end
+type classReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *classReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || r.pos+n > len(r.data) {
+		r.err = errClassFormat
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *classReader) skip(n int) {
+	r.bytes(n)
+}
+
+func (r *classReader) u1() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *classReader) u2() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *classReader) u4() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
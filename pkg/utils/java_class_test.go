@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+// openFeignJar ships configuration metadata together with the enum classes it refers to
+const openFeignJar = "../../src/test/resources/META-INF/rewrite/classpath/spring-cloud-openfeign-core-4.1.0.jar"
+
+// poolConcurrencyPolicy is an enum declared by openFeignJar
+const poolConcurrencyPolicy = "org.springframework.cloud.openfeign.support.FeignHttpClientProperties$Hc5Properties$PoolConcurrencyPolicy"
+
+// warningLogger keeps the warnings it is given
+type warningLogger struct {
+	core.NullLogger
+	warnings []string
+}
+
+func (l *warningLogger) Warn(msg string, args ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(msg, args...))
+}
+
+func TestLoadEnumConstants(t *testing.T) {
+	paths := []string{openFeignJar}
+	metadata, err := LoadConfigurationMetadata(paths)
+	if err != nil {
+		t.Fatalf("LoadConfigurationMetadata() error = %v", err)
+	}
+	if err := LoadEnumConstants(metadata, paths, &core.NullLogger{}); err != nil {
+		t.Fatalf("LoadEnumConstants() error = %v", err)
+	}
+
+	if got, want := metadata.Enums[poolConcurrencyPolicy], []string{"LAX", "STRICT"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Enums[%s] = %v, want %v", poolConcurrencyPolicy, got, want)
+	}
+
+	index := NewMetadataIndex(metadata)
+	binding, ok := index.Resolve("spring.cloud.openfeign.httpclient.hc5.pool-concurrency-policy")
+	if !ok {
+		t.Fatal("pool-concurrency-policy is not resolved")
+	}
+	for _, value := range []string{"LAX", "strict", "Lax"} {
+		if err := binding.ValidateValue(value); err != nil {
+			t.Errorf("ValidateValue(%q) error = %v", value, err)
+		}
+	}
+	if err := binding.ValidateValue("sometimes"); err == nil || !strings.Contains(err.Error(), "expected one of: LAX, STRICT") {
+		t.Errorf("ValidateValue(\"sometimes\") error = %v, want the constants listed", err)
+	}
+}
+
+func TestReadEnumConstantsRejectsMalformedClasses(t *testing.T) {
+	for _, content := range [][]byte{nil, []byte("not a class"), {0xCA, 0xFE, 0xBA, 0xBE, 0, 0}} {
+		if _, _, err := readEnumConstants(content); err == nil {
+			t.Errorf("readEnumConstants(%q) error = nil", content)
+		}
+	}
+}
+
+func TestLoadEnumConstantsSkipsUnreadableEntries(t *testing.T) {
+	policyClass := strings.ReplaceAll(poolConcurrencyPolicy, ".", "/") + ".class"
+	policyContent, err := readJarEntry(openFeignJar, policyClass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jar bytes.Buffer
+	writer := zip.NewWriter(&jar)
+	for _, entry := range []struct {
+		name    string
+		content []byte
+	}{
+		{"com/example/Mode.class", []byte("not a class")},
+		{"lib/broken.jar", []byte("not a jar")},
+		{policyClass, policyContent},
+	} {
+		w, err := writer.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(entry.content)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	jarPath := filepath.Join(t.TempDir(), "classes.jar")
+	if err := ioutil.WriteFile(jarPath, jar.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := &ConfigurationMetadata{Properties: []*ConfigurationProperty{
+		{Name: "app.mode", Type: "com.example.Mode"},
+		{Name: "app.policy", Type: poolConcurrencyPolicy},
+	}}
+	logger := &warningLogger{}
+	if err := LoadEnumConstants(metadata, []string{jarPath}, logger); err != nil {
+		t.Fatalf("LoadEnumConstants() error = %v", err)
+	}
+	if got, want := metadata.Enums[poolConcurrencyPolicy], []string{"LAX", "STRICT"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Enums[%s] = %v, want %v", poolConcurrencyPolicy, got, want)
+	}
+	if _, ok := metadata.Enums["com.example.Mode"]; ok {
+		t.Error("Enums has the malformed class")
+	}
+	if len(logger.warnings) != 2 ||
+		!strings.Contains(logger.warnings[0], "com/example/Mode.class") ||
+		!strings.Contains(logger.warnings[1], "lib/broken.jar") {
+		t.Errorf("warnings = %q, want the malformed class and the broken jar", logger.warnings)
+	}
+}
+
+// readJarEntry returns the content of an entry of a jar file
+func readJarEntry(jarPath, name string) ([]byte, error) {
+	archive, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	for _, file := range archive.File {
+		if file.Name == name {
+			return readArchiveEntry(file)
+		}
+	}
+	return nil, fmt.Errorf("%s has no entry %s", jarPath, name)
+}
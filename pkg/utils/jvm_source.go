@@ -0,0 +1,526 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+var (
+	// annotationPattern matches the start of an annotation with arguments in
+	// masked source, including Kotlin use-site targets such as "@field:Value("
+	annotationPattern = regexp.MustCompile(`@(?:[a-z]+:)?([A-Za-z_$][\w$]*(?:\s*\.\s*[A-Za-z_$][\w$]*)*)\s*\(`)
+
+	// kotlinArrayOfPattern matches the arrayOf( call opening a Kotlin array value
+	kotlinArrayOfPattern = regexp.MustCompile(`^arrayOf\s*\(`)
+)
+
+// StringLiteral is a string literal in Java or Kotlin source
+type StringLiteral struct {
+	Start  int // offset of the content, after the opening quotes
+	End    int // offset just past the content, before the closing quotes
+	Quotes int // number of quotes delimiting the literal: 1, or 3 for text blocks and raw strings
+}
+
+// Bounds returns the offsets of the literal including its quotes
+func (l StringLiteral) Bounds() (int, int) {
+	return l.Start - l.Quotes, l.End + l.Quotes
+}
+
+// Annotation is an annotation with arguments found in Java or Kotlin source
+type Annotation struct {
+	Name       string // name as written, possibly qualified
+	Start      int    // offset of "@"
+	End        int    // offset just past the closing parenthesis
+	Attributes []AnnotationAttribute
+}
+
+// AnnotationAttribute is one element of an annotation; the single-element
+// form @A("x") is reported as "value"
+type AnnotationAttribute struct {
+	Name  string
+	Start int // offset of the value expression
+	End   int // offset just past the value expression
+	// Literals holds the string literals of a value that is a string literal or
+	// an array of them, and is nil for any other value
+	Literals []StringLiteral
+}
+
+// Attribute returns the first attribute with one of the given names, or nil
+func (a *Annotation) Attribute(names ...string) *AnnotationAttribute {
+	for i := range a.Attributes {
+		for _, name := range names {
+			if a.Attributes[i].Name == name {
+				return &a.Attributes[i]
+			}
+		}
+	}
+	return nil
+}
+
+// MethodCall is a method call found in Java or Kotlin source whose first
+// argument is a string literal
+type MethodCall struct {
+	Start    int // offset of the receiver, or of the "." when any receiver matches
+	End      int // offset just past the closing parenthesis, or a Kotlin trailing lambda
+	Argument StringLiteral
+}
+
+// FindStringLiterals returns every string literal of Java or Kotlin source.
+// Comments, character literals and Kotlin string templates are skipped.
+func FindStringLiterals(source string, fileType core.FileType) []StringLiteral {
+	literals, _ := scanSource(source, fileType)
+	return literals
+}
+
+// FindAnnotations returns the annotations with arguments whose simple name is
+// one of names, e.g. "ConditionalOnProperty" also matches
+// "@org.springframework.boot.autoconfigure.condition.ConditionalOnProperty(...)"
+func FindAnnotations(source string, fileType core.FileType, names ...string) []Annotation {
+	literals, masked := scanSource(source, fileType)
+
+	var annotations []Annotation
+	for _, match := range annotationPattern.FindAllStringSubmatchIndex(masked, -1) {
+		name := strings.Join(strings.Fields(source[match[2]:match[3]]), "")
+		simpleName := name[strings.LastIndex(name, ".")+1:]
+		if !containsString(names, simpleName) {
+			continue
+		}
+		end := matchingBracket(masked, match[1]-1)
+		if end < 0 {
+			continue
+		}
+		annotations = append(annotations, Annotation{
+			Name:       name,
+			Start:      match[0],
+			End:        end + 1,
+			Attributes: parseAnnotationAttributes(masked, literals, match[1], end),
+		})
+	}
+	return annotations
+}
+
+// FindMethodCalls returns the calls of the named methods that pass a string
+// literal as first argument, such as env.getProperty("server.port", Integer.class).
+// A non-empty receiver restricts them to calls on that variable.
+func FindMethodCalls(source string, fileType core.FileType, receiver string, methods ...string) []MethodCall {
+	literals, masked := scanSource(source, fileType)
+	prefix := ""
+	if receiver != "" {
+		prefix = `\b` + regexp.QuoteMeta(receiver) + `\s*`
+	}
+	pattern := regexp.MustCompile(prefix + `(?:\?)?\.\s*(?:` + strings.Join(quoteMetaAll(methods), "|") + `)\s*(\()\s*"`)
+
+	var calls []MethodCall
+	for _, match := range pattern.FindAllStringSubmatchIndex(masked, -1) {
+		literal, ok := literalAt(literals, match[1]-1)
+		if !ok {
+			continue
+		}
+		end := matchingBracket(masked, match[2])
+		if end < 0 {
+			continue
+		}
+		if fileType == core.Kotlin {
+			// A trailing lambda on the same line is the last argument of the call
+			next := end + 1
+			for next < len(masked) && (masked[next] == ' ' || masked[next] == '\t') {
+				next++
+			}
+			if next < len(masked) && masked[next] == '{' {
+				if lambdaEnd := matchingBracket(masked, next); lambdaEnd >= 0 {
+					end = lambdaEnd
+				}
+			}
+		}
+		calls = append(calls, MethodCall{Start: match[0], End: end + 1, Argument: literal})
+	}
+	return calls
+}
+
+// FindVariables returns the names of the parameters and variables declared
+// with the given simple type name, e.g. "registry" for both the Java
+// "DynamicPropertyRegistry registry" and the Kotlin "registry: DynamicPropertyRegistry"
+func FindVariables(source string, fileType core.FileType, typeName string) []string {
+	_, masked := scanSource(source, fileType)
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`\b` + regexp.QuoteMeta(typeName) + `\s+([A-Za-z_$][\w$]*)`),
+		regexp.MustCompile(`\b([A-Za-z_$][\w$]*)\s*:\s*` + regexp.QuoteMeta(typeName) + `\b`),
+	}
+
+	var names []string
+	for _, pattern := range patterns {
+		for _, match := range pattern.FindAllStringSubmatch(masked, -1) {
+			if !containsString(names, match[1]) {
+				names = append(names, match[1])
+			}
+		}
+	}
+	return names
+}
+
+// RenameLiteralPropertyReferences rewrites the property references in the
+// placeholders and SpEL expressions of a string literal, as
+// RenamePropertyReferences does, and returns the new content of the literal.
+// Kotlin's escaped "\${" and raw "${'$'}{" or "${"$"}{" are read as the "${" Spring sees,
+// while Kotlin string templates are not placeholders.
+func RenameLiteralPropertyReferences(source string, literal StringLiteral, fileType core.FileType, rename func(key string) (string, bool)) (string, bool) {
+	content := source[literal.Start:literal.End]
+	if fileType != core.Kotlin {
+		return RenamePropertyReferences(content, rename)
+	}
+
+	view, offsets := kotlinLiteralView(content, literal.Quotes == 3)
+	var b strings.Builder
+	last := 0
+	modified := false
+	for _, reference := range FindPropertyReferences(view) {
+		newKey, ok := rename(reference.Key)
+		if !ok || newKey == reference.Key {
+			continue
+		}
+		start, end := offsets[reference.Start], offsets[reference.End-1]+1
+		if start < last {
+			continue
+		}
+		b.WriteString(content[last:start])
+		b.WriteString(newKey)
+		last = end
+		modified = true
+	}
+	if !modified {
+		return content, false
+	}
+	b.WriteString(content[last:])
+	return b.String(), true
+}
+
+// kotlinLiteralView returns the text Spring receives for the content of a
+// Kotlin string literal as far as "$" is concerned, with the offset in content
+// of each of its bytes. String templates are blanked out.
+func kotlinLiteralView(content string, raw bool) (string, []int) {
+	var view []byte
+	var offsets []int
+	emit := func(c byte, offset int) {
+		view = append(view, c)
+		offsets = append(offsets, offset)
+	}
+
+	for i := 0; i < len(content); i++ {
+		switch {
+		case !raw && content[i] == '\\' && i+1 < len(content):
+			if content[i+1] != '$' {
+				emit(content[i], i)
+			}
+			emit(content[i+1], i+1)
+			i++
+		case raw && (strings.HasPrefix(content[i:], "${'$'}") || strings.HasPrefix(content[i:], `${"$"}`)):
+			emit('$', i)
+			i += len("${'$'}") - 1
+		case content[i] == '$' && i+1 < len(content) && content[i+1] == '{':
+			// A string template: its expression is Kotlin code, not a placeholder
+			end := kotlinTemplateEnd(content, i+2)
+			if end < 0 {
+				end = len(content) - 1
+			}
+			for j := i; j <= end; j++ {
+				emit(' ', j)
+			}
+			i = end
+		default:
+			emit(content[i], i)
+		}
+	}
+	return string(view), offsets
+}
+
+// parseAnnotationAttributes parses the elements between the parentheses of an
+// annotation, found at masked[start:end]
+func parseAnnotationAttributes(masked string, literals []StringLiteral, start, end int) []AnnotationAttribute {
+	var attributes []AnnotationAttribute
+	for _, element := range splitArguments(masked, start, end) {
+		name := "value"
+		valueStart := element[0]
+		text := masked[element[0]:element[1]]
+		if eq := strings.Index(text, "="); eq > 0 && !strings.HasPrefix(text[eq:], "==") {
+			if candidate := strings.TrimSpace(text[:eq]); isIdentifier(candidate) {
+				name = candidate
+				valueStart = element[0] + eq + 1
+			}
+		}
+		for valueStart < element[1] && isSourceWhitespace(masked[valueStart]) {
+			valueStart++
+		}
+		valueEnd := element[1]
+		for valueEnd > valueStart && isSourceWhitespace(masked[valueEnd-1]) {
+			valueEnd--
+		}
+		if valueStart == valueEnd {
+			continue
+		}
+		attributes = append(attributes, AnnotationAttribute{
+			Name:     name,
+			Start:    valueStart,
+			End:      valueEnd,
+			Literals: literalValues(masked, literals, valueStart, valueEnd),
+		})
+	}
+	return attributes
+}
+
+// literalValues returns the literals of a value that consists only of string
+// literals, optionally in a Java array initializer or a Kotlin array literal
+// or arrayOf call, or nil
+func literalValues(masked string, literals []StringLiteral, start, end int) []StringLiteral {
+	if match := kotlinArrayOfPattern.FindStringIndex(masked[start:end]); match != nil {
+		start += match[1] - 1
+	}
+
+	var values []StringLiteral
+	for i := start; i < end; i++ {
+		c := masked[i]
+		switch {
+		case c == '"':
+			literal, ok := literalAt(literals, i)
+			if !ok {
+				return nil
+			}
+			values = append(values, literal)
+			_, i = literal.Bounds()
+			i--
+		case strings.IndexByte("{}[](),", c) >= 0 || isSourceWhitespace(c):
+		default:
+			// Constants, concatenations and other expressions are not plain keys
+			return nil
+		}
+	}
+	return values
+}
+
+// splitArguments splits masked[start:end] at the commas outside brackets
+func splitArguments(masked string, start, end int) [][2]int {
+	var arguments [][2]int
+	depth := 0
+	from := start
+	for i := start; i < end; i++ {
+		switch masked[i] {
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				arguments = append(arguments, [2]int{from, i})
+				from = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(masked[from:end]) != "" || len(arguments) > 0 {
+		arguments = append(arguments, [2]int{from, end})
+	}
+	return arguments
+}
+
+// matchingBracket returns the offset of the bracket closing the one at open, or -1
+func matchingBracket(masked string, open int) int {
+	depth := 0
+	for i := open; i < len(masked); i++ {
+		switch masked[i] {
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// literalAt returns the literal whose opening quotes start at quote
+func literalAt(literals []StringLiteral, quote int) (StringLiteral, bool) {
+	for _, literal := range literals {
+		if start, _ := literal.Bounds(); start == quote {
+			return literal, true
+		}
+	}
+	return StringLiteral{}, false
+}
+
+// scanSource returns the string literals of Java or Kotlin source, and a copy
+// of the source in which comments and the content of literals are blanked out
+// so that code structure can be matched safely
+func scanSource(source string, fileType core.FileType) ([]StringLiteral, string) {
+	kotlin := fileType == core.Kotlin
+	var literals []StringLiteral
+	masked := []byte(source)
+	blank := func(start, end int) {
+		for i := start; i < end; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(source); i++ {
+		switch {
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				end = len(source) - i
+			}
+			blank(i, i+end)
+			i += end
+		case strings.HasPrefix(source[i:], "/*"):
+			end := blockCommentEnd(source, i, kotlin)
+			if end < 0 {
+				blank(i, len(source))
+				return literals, string(masked)
+			}
+			blank(i, end)
+			i = end - 1
+		case source[i] == '"' || source[i] == '\'':
+			literal, end := scanLiteral(source, i, kotlin)
+			if end < 0 {
+				blank(i+1, len(source))
+				return literals, string(masked)
+			}
+			if source[i] == '"' {
+				literals = append(literals, literal)
+			}
+			blank(literal.Start, literal.End)
+			i = end - 1
+		}
+	}
+	return literals, string(masked)
+}
+
+// scanLiteral reads the string or character literal starting at i and returns
+// it with the offset just past its closing quotes, or -1 when it is unterminated
+func scanLiteral(source string, i int, kotlin bool) (StringLiteral, int) {
+	if strings.HasPrefix(source[i:], `"""`) {
+		start := i + 3
+		end := literalEnd(source, start, `"""`, !kotlin, kotlin)
+		if end < 0 {
+			return StringLiteral{}, -1
+		}
+		// Kotlin raw strings may end with extra quotes that belong to the content
+		for kotlin && end+3 < len(source) && source[end+3] == '"' {
+			end++
+		}
+		return StringLiteral{Start: start, End: end, Quotes: 3}, end + 3
+	}
+
+	delimiter := source[i : i+1]
+	end := literalEnd(source, i+1, delimiter, true, kotlin && delimiter == `"`)
+	if end < 0 {
+		return StringLiteral{}, -1
+	}
+	return StringLiteral{Start: i + 1, End: end, Quotes: 1}, end + 1
+}
+
+// literalEnd returns the offset of the closing delimiter of a literal whose
+// content starts at from, or -1. Escapes are skipped when escapes is set, and
+// Kotlin string templates when templates is set.
+func literalEnd(source string, from int, delimiter string, escapes, templates bool) int {
+	for i := from; i < len(source); i++ {
+		switch {
+		case escapes && source[i] == '\\':
+			i++
+		case templates && strings.HasPrefix(source[i:], "${"):
+			end := kotlinTemplateEnd(source, i+2)
+			if end < 0 {
+				return -1
+			}
+			i = end
+		case strings.HasPrefix(source[i:], delimiter):
+			return i
+		case source[i] == '\n' && len(delimiter) == 1:
+			// Unterminated literal
+			return -1
+		}
+	}
+	return -1
+}
+
+// kotlinTemplateEnd returns the offset of the "}" closing a string template
+// whose expression starts at from, skipping nested literals, or -1
+func kotlinTemplateEnd(source string, from int) int {
+	depth := 0
+	for i := from; i < len(source); i++ {
+		switch source[i] {
+		case '"', '\'':
+			_, end := scanLiteral(source, i, true)
+			if end < 0 {
+				return -1
+			}
+			i = end - 1
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// blockCommentEnd returns the offset just past the block comment starting at
+// i, or -1; Kotlin block comments nest
+func blockCommentEnd(source string, i int, nested bool) int {
+	depth := 0
+	for j := i; j < len(source)-1; j++ {
+		switch {
+		case source[j] == '/' && source[j+1] == '*':
+			if depth == 0 || nested {
+				depth++
+			}
+			j++
+		case source[j] == '*' && source[j+1] == '/':
+			depth--
+			j++
+			if depth == 0 {
+				return j + 1
+			}
+		}
+	}
+	return -1
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !isASCIIAlphanumeric(c) && c != '_' && c != '$' || (i == 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isSourceWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteMetaAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	return quoted
+}
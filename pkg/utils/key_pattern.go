@@ -0,0 +1,302 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// KeyPattern matches property keys, and the keys beneath them, against a key
+// glob or a regular expression. Globs follow Spring's relaxed binding, so
+// "spring.datasource.driver-class-name" also matches "driverClassName" and
+// SPRING_DATASOURCE_DRIVERCLASSNAME. Parenthesised parts of a glob such as
+// "(*)" capture segments that a replacement refers to as $1, $2, ... and "[*]"
+// matches a list index that a "[*]" of the replacement keeps.
+type KeyPattern struct {
+	Pattern  string
+	regex    *regexp.Regexp
+	envRegex *regexp.Regexp // nil for regular expressions
+	// indexGroups tells, per capture group of a glob, whether it holds the index matched by "[*]"
+	indexGroups []bool
+}
+
+var (
+	keyPatternCache   = make(map[string]*KeyPattern)
+	keyPatternCacheMu sync.Mutex
+)
+
+// CompileKeyPattern compiles a key glob, or a regular expression when isRegex is
+// set; regular expressions are matched against keys exactly as written
+func CompileKeyPattern(pattern string, isRegex bool) (*KeyPattern, error) {
+	body := pattern
+	if !isRegex {
+		body = keyGlobToRegexBody(pattern)
+	}
+	// The last group holds the part of a subkey below the matched key
+	regex, err := regexp.Compile(`^(?:` + body + `)((?:[.\[].*)?)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+	}
+
+	compiled := &KeyPattern{Pattern: pattern, regex: regex}
+	if !isRegex {
+		compiled.envRegex = regexp.MustCompile(`^(?:` + keyGlobToEnvironmentRegexBody(pattern) + `)((?:_.*)?)$`)
+		compiled.indexGroups = globIndexGroups(pattern)
+	}
+	return compiled, nil
+}
+
+// cachedKeyPattern compiles a key glob once for the package-level match helpers
+func cachedKeyPattern(pattern string) (*KeyPattern, error) {
+	keyPatternCacheMu.Lock()
+	defer keyPatternCacheMu.Unlock()
+
+	if compiled, ok := keyPatternCache[pattern]; ok {
+		return compiled, nil
+	}
+	compiled, err := CompileKeyPattern(pattern, false)
+	if err != nil {
+		return nil, err
+	}
+	keyPatternCache[pattern] = compiled
+	return compiled, nil
+}
+
+// Matches reports whether key matches the pattern or lies beneath a key that does
+func (p *KeyPattern) Matches(key string) bool {
+	_, ok := p.Subkey(key)
+	return ok
+}
+
+// MatchesExactly reports whether key matches the pattern itself
+func (p *KeyPattern) MatchesExactly(key string) bool {
+	subkey, ok := p.Subkey(key)
+	return ok && subkey == ""
+}
+
+// Subkey returns the part of key below the matched key without its leading
+// separator, e.g. "active" for "spring.profiles.active" and the pattern "spring.profiles"
+func (p *KeyPattern) Subkey(key string) (string, bool) {
+	regex, match := p.match(key)
+	if match == nil {
+		return "", false
+	}
+	last := len(match) - 2
+	subkey := key[match[last]:match[last+1]]
+	if regex == p.envRegex {
+		return strings.TrimPrefix(subkey, "_"), true
+	}
+	return strings.TrimPrefix(subkey, "."), true
+}
+
+// Replace rewrites the matched part of key using template, in which $1 or ${1}
+// refer to the captured parts; any subkey below the matched key is kept.
+// Environment variable names are rewritten to the environment variable form
+// of the new key.
+func (p *KeyPattern) Replace(key, template string) (string, bool) {
+	regex, match := p.match(key)
+	if match == nil {
+		return key, false
+	}
+	last := len(match) - 2
+	// Drop the subkey group so that templates cannot refer to it
+	replaced := string(regex.ExpandString(nil, p.groupTemplate(template), key, match[:last]))
+	if regex == p.envRegex {
+		replaced = ToEnvironmentVariableName(replaced)
+	}
+	return replaced + key[match[last]:match[last+1]], true
+}
+
+// groupTemplate rewrites a replacement template for the groups of the regular
+// expression: $n refers to the n-th parenthesised part of the glob, and each
+// "[*]" to the index matched by the corresponding "[*]" of the glob
+func (p *KeyPattern) groupTemplate(template string) string {
+	var captures, indexes []int
+	for i, isIndex := range p.indexGroups {
+		if isIndex {
+			indexes = append(indexes, i+1)
+		} else {
+			captures = append(captures, i+1)
+		}
+	}
+	if len(indexes) == 0 {
+		return template
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(template); i++ {
+		if strings.HasPrefix(template[i:], "[*]") && len(indexes) > 0 {
+			b.WriteString("[${" + strconv.Itoa(indexes[0]) + "}]")
+			indexes = indexes[1:]
+			i += 2
+			continue
+		}
+		if strings.HasPrefix(template[i:], "$$") {
+			b.WriteString("$$")
+			i++
+			continue
+		}
+		if template[i] == '$' {
+			start := i + 1
+			braced := start < len(template) && template[start] == '{'
+			if braced {
+				start++
+			}
+			end := start
+			for end < len(template) && template[end] >= '0' && template[end] <= '9' {
+				end++
+			}
+			if n, err := strconv.Atoi(template[start:end]); err == nil && (!braced || (end < len(template) && template[end] == '}')) {
+				if n >= 1 && n <= len(captures) {
+					n = captures[n-1]
+				}
+				b.WriteString("${" + strconv.Itoa(n) + "}")
+				if braced {
+					end++
+				}
+				i = end - 1
+				continue
+			}
+		}
+		b.WriteByte(template[i])
+	}
+	return b.String()
+}
+
+// match returns the regular expression used for key and its submatch indexes
+func (p *KeyPattern) match(key string) (*regexp.Regexp, []int) {
+	regex := p.regex
+	if p.envRegex != nil && IsEnvironmentVariableName(key) {
+		regex = p.envRegex
+	}
+	return regex, regex.FindStringSubmatchIndex(key)
+}
+
+// keyGlobToRegexBody converts a key glob to an unanchored regular expression
+// that ignores case, dashes and underscores outside brackets and keeps
+// parentheses as capture groups; "[*]" matches any list index and "[(*)]"
+// captures it
+func keyGlobToRegexBody(pattern string) string {
+	var b strings.Builder
+	inBrackets := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case inBrackets:
+			if c == ']' {
+				inBrackets = false
+			}
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		case c == '[':
+			if closing := strings.IndexByte(pattern[i:], ']'); closing > 0 && isDigits(pattern[i+1:i+closing]) {
+				// An index also matches the equivalent ".0" segment
+				index := pattern[i+1 : i+closing]
+				b.WriteString(`(?:\[` + index + `\]|\.` + index + `)`)
+				i += closing
+				continue
+			}
+			if strings.HasPrefix(pattern[i:], "[*]") {
+				// Any index of a list, also as a ".0" segment
+				b.WriteString(`[.\[](\d+)\]?`)
+				i += 2
+				continue
+			}
+			if strings.HasPrefix(pattern[i:], "[(*)]") {
+				b.WriteString(`\[(\d+)\]`)
+				i += 4
+				continue
+			}
+			inBrackets = true
+			b.WriteString(`\[`)
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".+")
+			i++
+		case c == '*':
+			// A segment ends at a list index, as it does in the ".0" and "_0_" forms
+			b.WriteString(`[^.\[\]]+`)
+		case c == '?':
+			b.WriteString(`[^.\[\]]`)
+		case c == '(' || c == ')':
+			b.WriteByte(c)
+		case c == '-' || c == '_':
+			b.WriteString(`[-_]*`)
+		case isASCIILetter(c):
+			b.WriteString("[" + strings.ToLower(string(c)) + strings.ToUpper(string(c)) + "]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+		// Dashes and underscores may separate any two characters of a name
+		if !inBrackets && isASCIIAlphanumeric(c) && i+1 < len(pattern) && isASCIIAlphanumeric(pattern[i+1]) {
+			b.WriteString(`[-_]*`)
+		}
+	}
+	return b.String()
+}
+
+// keyGlobToEnvironmentRegexBody converts a key glob to a regular expression
+// matching the environment variable form of the keys it matches
+func keyGlobToEnvironmentRegexBody(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString("[A-Z0-9_]+")
+			i++
+		case c == '*':
+			b.WriteString("[A-Z0-9]+")
+		case c == '?':
+			b.WriteString("[A-Z0-9]")
+		case c == '(' || c == ')':
+			b.WriteByte(c)
+		case strings.HasPrefix(pattern[i:], "[*]") || strings.HasPrefix(pattern[i:], "[(*)]"):
+			b.WriteString("_([0-9]+)")
+			i = strings.IndexByte(pattern[i:], ']') + i
+		case c == '.' || c == '[':
+			b.WriteString("_")
+		case c == '-' || c == '_' || c == ']':
+		default:
+			b.WriteString(regexp.QuoteMeta(strings.ToUpper(string(c))))
+		}
+	}
+	return b.String()
+}
+
+// globIndexGroups tells, per capture group of the regular expression compiled
+// from a key glob, whether it holds the index matched by "[*]"
+func globIndexGroups(pattern string) []bool {
+	var groups []bool
+	inBrackets := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case inBrackets:
+			inBrackets = c != ']'
+		case strings.HasPrefix(pattern[i:], "[*]"):
+			groups = append(groups, true)
+			i += 2
+		case strings.HasPrefix(pattern[i:], "[(*)]"):
+			groups = append(groups, false)
+			i += 4
+		case c == '[':
+			closing := strings.IndexByte(pattern[i:], ']')
+			if closing > 0 && isDigits(pattern[i+1:i+closing]) {
+				i += closing
+			} else {
+				inBrackets = true
+			}
+		case c == '(':
+			groups = append(groups, false)
+		}
+	}
+	return groups
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isASCIIAlphanumeric(c byte) bool {
+	return isASCIILetter(c) || (c >= '0' && c <= '9')
+}
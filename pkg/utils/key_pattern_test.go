@@ -0,0 +1,115 @@
+package utils
+
+import "testing"
+
+func TestKeyPatternMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		regex   bool
+		key     string
+		matches bool
+		subkey  string
+	}{
+		{"exact key", "spring.redis", false, "spring.redis", true, ""},
+		{"subkey", "spring.redis", false, "spring.redis.host", true, "host"},
+		{"indexed subkey", "app.servers", false, "app.servers[0].host", true, "[0].host"},
+		{"segment boundary", "spring.redis", false, "spring.redisson.host", false, ""},
+		{"camel case", "spring.datasource.driver-class-name", false, "spring.datasource.driverClassName", true, ""},
+		{"underscores", "spring.datasource.driver-class-name", false, "spring.datasource.driver_class_name", true, ""},
+		{"upper case", "spring.datasource.url", false, "Spring.DataSource.URL", true, ""},
+		{"environment variable", "spring.datasource.driver-class-name", false, "SPRING_DATASOURCE_DRIVERCLASSNAME", true, ""},
+		{"environment variable subkey", "spring.datasource", false, "SPRING_DATASOURCE_URL", true, "URL"},
+		{"environment variable index", "app.servers[*].host", false, "APP_SERVERS_0_HOST", true, ""},
+		{"star within a segment", "server.*.enabled", false, "server.ssl.enabled", true, ""},
+		{"star not across segments", "server.*.enabled", false, "server.ssl.key.enabled", false, ""},
+		{"star not across an index", "app.*.host", false, "app.servers[0].host", false, ""},
+		{"star before an index", "app.*", false, "app.servers[0]", true, "[0]"},
+		{"double star", "server.**.enabled", false, "server.ssl.key.enabled", true, ""},
+		{"question mark", "app.v?", false, "app.v1", true, ""},
+		{"any index", "app.servers[*].host", false, "app.servers[12].host", true, ""},
+		{"any index as segment", "app.servers[*].host", false, "app.servers.12.host", true, ""},
+		{"fixed index", "app.servers[1]", false, "app.servers.1.host", true, "host"},
+		{"fixed index mismatch", "app.servers[1]", false, "app.servers[10]", false, ""},
+		{"bracketed map key", "logging.level[com.example]", false, "logging.level[com.example]", true, ""},
+		{"regex", `spring\.(redis|mongo)`, true, "spring.mongo.uri", true, "uri"},
+		{"regex is anchored at the start", `redis\.host`, true, "spring.redis.host", false, ""},
+		{"regex matching part of a segment", `spring\.red`, true, "spring.redis", false, ""},
+		{"regex matching part of a key", `spring\.redis`, true, "spring.redisson.host", false, ""},
+		{"regex without relaxed binding", `spring\.datasource\.driver-class-name`, true, "spring.datasource.driverClassName", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := CompileKeyPattern(tt.pattern, tt.regex)
+			if err != nil {
+				t.Fatalf("CompileKeyPattern(%q) error = %v", tt.pattern, err)
+			}
+			subkey, ok := pattern.Subkey(tt.key)
+			if ok != tt.matches || subkey != tt.subkey {
+				t.Errorf("Subkey(%q) = %q, %v, want %q, %v", tt.key, subkey, ok, tt.subkey, tt.matches)
+			}
+			if exact := pattern.MatchesExactly(tt.key); exact != (tt.matches && tt.subkey == "") {
+				t.Errorf("MatchesExactly(%q) = %v", tt.key, exact)
+			}
+		})
+	}
+}
+
+func TestKeyPatternReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		regex    bool
+		key      string
+		template string
+		expected string
+	}{
+		{"rename keeps the subkey", "spring.redis", false, "spring.redis.host", "spring.data.redis", "spring.data.redis.host"},
+		{"capture", "spring.(*).host", false, "spring.redis.host", "spring.data.$1.host", "spring.data.redis.host"},
+		{"braced capture", "app.(*)", false, "app.timeout", "app.${1}-ms", "app.timeout-ms"},
+		{"several captures", "(*).(*).enabled", false, "server.ssl.enabled", "$2.$1.on", "ssl.server.on"},
+		{"double star capture", "app.(**)", false, "app.servers[0].host", "legacy.$1", "legacy.servers[0].host"},
+		{"capture before an index", "app.(*)", false, "app.servers[0].host", "new.$1", "new.servers[0].host"},
+		{"capture after an index", "app.servers[*].(*)", false, "app.servers[2].host", "app.nodes[*].$1", "app.nodes[2].host"},
+		{"index as segment", "app.servers[*].(*)", false, "app.servers.2.host", "app.nodes[*].$1", "app.nodes[2].host"},
+		{"captures across indexes", "a[*].(*)[*].(*)", false, "a[1].b[2].c", "x[*].$1[*].y.$2", "x[1].b[2].y.c"},
+		{"captured index", "app.servers[(*)].host", false, "app.servers[3].host", "app.host-$1", "app.host-3"},
+		{"dollar sign", "app.(*)", false, "app.cost", "app.$$1.$1", "app.$1.cost"},
+		{"environment variable", "spring.redis", false, "SPRING_REDIS_HOST", "spring.data.redis", "SPRING_DATA_REDIS_HOST"},
+		{"environment variable index", "app.servers[*].(*)", false, "APP_SERVERS_2_HOST", "app.nodes[*].$1", "APP_NODES_2_HOST"},
+		{"regex", `spring\.(redis|mongo)\.(.*)`, true, "spring.redis.host", "spring.data.$1.$2", "spring.data.redis.host"},
+		{"regex keeps the subkey", `spring\.(redis)`, true, "spring.redis.host", "spring.data.$1", "spring.data.redis.host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := CompileKeyPattern(tt.pattern, tt.regex)
+			if err != nil {
+				t.Fatalf("CompileKeyPattern(%q) error = %v", tt.pattern, err)
+			}
+			actual, ok := pattern.Replace(tt.key, tt.template)
+			if !ok || actual != tt.expected {
+				t.Errorf("Replace(%q, %q) = %q, %v, want %q", tt.key, tt.template, actual, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKeyPatternReplaceWithoutMatch(t *testing.T) {
+	pattern, err := CompileKeyPattern("app.(*).host", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"app.servers[0].host", "app.servers.0.host", "APP_SERVERS_0_HOST", "other.key"} {
+		if actual, ok := pattern.Replace(key, "new.$1"); ok || actual != key {
+			t.Errorf("Replace(%q) = %q, %v, want the key unchanged", key, actual, ok)
+		}
+	}
+}
+
+func TestCompileKeyPatternRejectsInvalidRegex(t *testing.T) {
+	if _, err := CompileKeyPattern("spring.(redis", true); err == nil {
+		t.Error("CompileKeyPattern() error = nil")
+	}
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+// DefaultParserRegistry holds the built-in file type registrations used by
+// LoadSourceFile and DetermineFileType
+var DefaultParserRegistry = NewDefaultParserRegistry()
+
+// NewDefaultParserRegistry creates a registry with all built-in file types
+func NewDefaultParserRegistry() *core.ParserRegistry {
+	registry := core.NewParserRegistry()
+	RegisterDefaultParsers(registry)
+	return registry
+}
+
+// RegisterDefaultParsers registers the built-in file types, most specific first
+func RegisterDefaultParsers(registry *core.ParserRegistry) {
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.MavenPOM,
+		Detect: fileNameMatcher("pom.xml"),
+		Parser: NewTextParser(core.MavenPOM),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.GradleKotlin,
+		Detect: fileNameMatcher("*.gradle.kts"),
+		Parser: NewTextParser(core.GradleKotlin),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.GradleGroovy,
+		Detect: fileNameMatcher("*.gradle"),
+		Parser: NewTextParser(core.GradleGroovy),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.Properties,
+		Detect: fileNameMatcher("*.properties"),
+		Parser: NewTextParser(core.Properties),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.YAML,
+		Detect: fileNameMatcher("*.yml", "*.yaml"),
+		Parser: NewTextParser(core.YAML),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.Java,
+		Detect: fileNameMatcher("*.java"),
+		Parser: NewTextParser(core.Java),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.Kotlin,
+		Detect: fileNameMatcher("*.kt", "*.kts"),
+		Parser: NewTextParser(core.Kotlin),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.Dockerfile,
+		Detect: withoutBackups(fileNameMatcher("dockerfile", "dockerfile.*", "*.dockerfile", "containerfile")),
+		Parser: NewTextParser(core.Dockerfile),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.DotEnv,
+		Detect: withoutBackups(fileNameMatcher(".env", ".env.*", "*.env")),
+		Parser: NewTextParser(core.DotEnv),
+	})
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.XML,
+		Detect: fileNameMatcher("*.xml"),
+		Parser: NewTextParser(core.XML),
+	})
+	// Anything else is treated as plain text, as long as it is not binary
+	registry.Register(core.FileTypeRegistration{
+		Type:   core.PlainText,
+		Detect: func(string) bool { return true },
+		Parser: core.ParserFunc(parsePlainText),
+	})
+}
+
+// NewTextParser creates a parser that decodes a file as text of the given type
+func NewTextParser(fileType core.FileType) core.Parser {
+	return core.ParserFunc(func(path string, data []byte) (core.SourceFile, error) {
+		content, encoding := core.DecodeContent(data, fileType)
+		return &core.SpringConfigFile{
+			Path:     path,
+			Content:  content,
+			Type:     fileType,
+			Kind:     ClassifySourceFile(path, fileType, content),
+			Encoding: encoding,
+		}, nil
+	})
+}
+
+// parsePlainText parses an unrecognised file as plain text, skipping binary files
+func parsePlainText(path string, data []byte) (core.SourceFile, error) {
+	if isBinary(data) {
+		return nil, fmt.Errorf("%w: %s is binary", core.ErrUnsupportedFile, path)
+	}
+	return NewTextParser(core.PlainText).Parse(path, data)
+}
+
+// fileNameMatcher creates a detection rule matching the lower-cased base name against globs
+func fileNameMatcher(patterns ...string) func(path string) bool {
+	return func(path string) bool {
+		fileName := strings.ToLower(filepath.Base(path))
+		for _, pattern := range patterns {
+			if matched, _ := MatchGlob(fileName, pattern); matched {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// withoutBackups keeps a detection rule from matching the ".backup" copies the
+// runner leaves next to modified files, such as "Dockerfile.backup"
+func withoutBackups(detect func(path string) bool) func(path string) bool {
+	return func(path string) bool {
+		return !strings.HasSuffix(strings.ToLower(path), ".backup") && detect(path)
+	}
+}
+
+// isBinary uses the same heuristic as git: a NUL byte near the start of the
+// file. UTF-16 and UTF-32 text holds NUL bytes too, so files starting with a
+// byte order mark are text.
+func isBinary(data []byte) bool {
+	if core.HasUnicodeBOM(data) {
+		return false
+	}
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openrewrite/rewrite-spring-go/pkg/core"
+)
+
+func TestParsePlainText(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		content string
+		binary  bool
+	}{
+		{"UTF-8", []byte("hello\n"), "hello\n", false},
+		{"UTF-16LE", []byte("\xff\xfeh\x00i\x00\n\x00"), "hi\n", false},
+		{"UTF-16BE", []byte("\xfe\xff\x00h\x00i\x00\n"), "hi\n", false},
+		{"UTF-32LE", []byte("\xff\xfe\x00\x00h\x00\x00\x00\n\x00\x00\x00"), "h\n", false},
+		{"UTF-32BE", []byte("\x00\x00\xfe\xff\x00\x00\x00h\x00\x00\x00\n"), "h\n", false},
+		{"binary", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceFile, err := parsePlainText("notes.dat", tt.data)
+			if tt.binary {
+				if !errors.Is(err, core.ErrUnsupportedFile) {
+					t.Errorf("parsePlainText() error = %v, want ErrUnsupportedFile", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePlainText() error = %v", err)
+			}
+			if sourceFile.GetType() != core.PlainText || sourceFile.GetContent() != tt.content {
+				t.Errorf("parsePlainText() = %v %q, want PlainText %q", sourceFile.GetType(), sourceFile.GetContent(), tt.content)
+			}
+		})
+	}
+}
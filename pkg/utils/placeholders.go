@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// spelEnvironmentPattern matches property lookups on the environment inside a
+// SpEL expression, e.g. environment['server.port'] or
+// @environment.getProperty("server.port")
+var spelEnvironmentPattern = regexp.MustCompile(`environment(?:\[\s*|\.(?:getProperty|getRequiredProperty|containsProperty)\(\s*)(?:'([^']*)'|"([^"]*)")`)
+
+// Placeholder is a "${key:default}" property placeholder found in a text.
+// Offsets are byte offsets into the text that was parsed.
+type Placeholder struct {
+	Start    int // offset of "${"
+	End      int // offset just past the closing "}"
+	Key      string
+	KeyStart int
+	KeyEnd   int
+	// Default is the text after the first ":", which may hold further placeholders
+	Default    string
+	HasDefault bool
+	// Nested holds the placeholders inside the key or the default
+	Nested []Placeholder
+}
+
+// PropertyReference is the key of a property referenced from a text, either by
+// a placeholder or by a SpEL environment lookup
+type PropertyReference struct {
+	Key   string
+	Start int // offset of the key
+	End   int // offset just past the key
+}
+
+// ParsePlaceholders returns the outermost placeholders of a text, with those
+// nested in keys and defaults attached. A backslash directly before "${"
+// escapes it, and unterminated placeholders are ignored as Spring does.
+// Placeholders inside "#{...}" SpEL expressions are found like any other.
+func ParsePlaceholders(text string) []Placeholder {
+	return parsePlaceholders(text, 0, len(text))
+}
+
+// parsePlaceholders returns the placeholders within text[from:to]
+func parsePlaceholders(text string, from, to int) []Placeholder {
+	var placeholders []Placeholder
+	for i := from; i < to-1; i++ {
+		if text[i] == '\\' && strings.HasPrefix(text[i+1:to], "${") {
+			i += 2
+			continue
+		}
+		if text[i] != '$' || text[i+1] != '{' {
+			continue
+		}
+		end := placeholderEnd(text, i+2, to)
+		if end < 0 {
+			break
+		}
+
+		placeholder := Placeholder{Start: i, End: end + 1, KeyStart: i + 2, KeyEnd: end}
+		if separator := placeholderSeparator(text, i+2, end); separator >= 0 {
+			placeholder.KeyEnd = separator
+			placeholder.Default = text[separator+1 : end]
+			placeholder.HasDefault = true
+		}
+		placeholder.Key = text[placeholder.KeyStart:placeholder.KeyEnd]
+		placeholder.Nested = parsePlaceholders(text, i+2, end)
+		placeholders = append(placeholders, placeholder)
+		i = end
+	}
+	return placeholders
+}
+
+// placeholderEnd returns the offset of the "}" closing a placeholder whose
+// content starts at from, counting nested braces, or -1
+func placeholderEnd(text string, from, to int) int {
+	depth := 0
+	for i := from; i < to; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// placeholderSeparator returns the offset of the ":" separating the key of a
+// placeholder from its default, ignoring those in nested placeholders, or -1
+func placeholderSeparator(text string, from, to int) int {
+	depth := 0
+	for i := from; i < to; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ':':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// FindPropertyReferences returns the property keys a text refers to, in order:
+// the keys of placeholders at any nesting depth, and the keys looked up from
+// the environment in SpEL expressions. Keys built from other placeholders,
+// such as "${app.${env}.url}", are not references to a single property.
+func FindPropertyReferences(text string) []PropertyReference {
+	var references []PropertyReference
+	var collect func(placeholders []Placeholder)
+	collect = func(placeholders []Placeholder) {
+		for _, placeholder := range placeholders {
+			if placeholder.Key != "" && !strings.Contains(placeholder.Key, "${") {
+				references = append(references, PropertyReference{
+					Key:   placeholder.Key,
+					Start: placeholder.KeyStart,
+					End:   placeholder.KeyEnd,
+				})
+			}
+			collect(placeholder.Nested)
+		}
+	}
+	collect(ParsePlaceholders(text))
+
+	for _, expression := range findSpELExpressions(text) {
+		for _, match := range spelEnvironmentPattern.FindAllStringSubmatchIndex(text[expression[0]:expression[1]], -1) {
+			group := 2
+			if match[group] < 0 {
+				group = 4
+			}
+			start, end := expression[0]+match[group], expression[0]+match[group+1]
+			references = append(references, PropertyReference{Key: text[start:end], Start: start, End: end})
+		}
+	}
+
+	sort.Slice(references, func(i, j int) bool { return references[i].Start < references[j].Start })
+	return references
+}
+
+// findSpELExpressions returns the offsets of the content of each "#{...}"
+// expression, skipping braces inside quoted SpEL strings
+func findSpELExpressions(text string) [][2]int {
+	var expressions [][2]int
+	for i := 0; i < len(text)-1; i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] != '#' || text[i+1] != '{' {
+			continue
+		}
+
+		depth := 0
+		var quote byte
+		end := -1
+		for j := i + 2; j < len(text) && end < 0; j++ {
+			c := text[j]
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '\'' || c == '"':
+				quote = c
+			case c == '{':
+				depth++
+			case c == '}':
+				if depth == 0 {
+					end = j
+				}
+				depth--
+			}
+		}
+		if end < 0 {
+			break
+		}
+		expressions = append(expressions, [2]int{i + 2, end})
+		i = end
+	}
+	return expressions
+}
+
+// RenamePropertyReferences rewrites the property keys a text refers to, as
+// found by FindPropertyReferences, using rename. It reports whether any key changed.
+func RenamePropertyReferences(text string, rename func(key string) (string, bool)) (string, bool) {
+	references := FindPropertyReferences(text)
+	var b strings.Builder
+	last := 0
+	modified := false
+	for _, reference := range references {
+		newKey, ok := rename(reference.Key)
+		if !ok || newKey == reference.Key || reference.Start < last {
+			continue
+		}
+		b.WriteString(text[last:reference.Start])
+		b.WriteString(newKey)
+		last = reference.End
+		modified = true
+	}
+	if !modified {
+		return text, false
+	}
+	b.WriteString(text[last:])
+	return b.String(), true
+}
@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PropertiesEntry is a key/value pair found in a properties file. A single
+// entry may span several physical lines when the value uses continuations.
+type PropertiesEntry struct {
+	Key        string // unescaped key
+	Value      string // raw value text with continuation lines joined
+	Line       int    // index of the line holding the key
+	End        int    // one past the last physical line of the entry
+	KeyStart   int    // offset of the key in Lines[Line]
+	KeyEnd     int    // offset just past the key in Lines[Line]
+	ValueStart int    // offset of the value in Lines[Line]
+}
+
+// PropertiesDocument is a line-oriented view of a properties file that keeps
+// comments, blank lines and formatting intact while exposing its entries
+type PropertiesDocument struct {
+	Lines   []string
+	Entries []*PropertiesEntry
+}
+
+// ParseProperties parses "\n"-separated properties content into a document
+func ParseProperties(content string) *PropertiesDocument {
+	doc := &PropertiesDocument{Lines: strings.Split(content, "\n")}
+	doc.reindex()
+	return doc
+}
+
+// String joins the document lines back into file content
+func (d *PropertiesDocument) String() string {
+	return strings.Join(d.Lines, "\n")
+}
+
+// Find returns the first entry with exactly the given key
+func (d *PropertiesDocument) Find(key string) *PropertiesEntry {
+	for _, entry := range d.Entries {
+		if entry.Key == key {
+			return entry
+		}
+	}
+	return nil
+}
+
+// SetValue replaces the value of an entry, collapsing any continuation lines
+func (d *PropertiesDocument) SetValue(entry *PropertiesEntry, value string) {
+	line := d.Lines[entry.Line]
+	d.Lines[entry.Line] = line[:entry.ValueStart] + value
+	d.RemoveLines(entry.Line+1, entry.End)
+}
+
+// SetKey replaces the key of an entry, keeping separator and value untouched
+func (d *PropertiesDocument) SetKey(entry *PropertiesEntry, key string) {
+	line := d.Lines[entry.Line]
+	d.Lines[entry.Line] = line[:entry.KeyStart] + EscapePropertiesKey(key) + line[entry.KeyEnd:]
+	d.reindex()
+}
+
+// RemoveLines deletes the lines in [start, end) and re-indexes the entries
+func (d *PropertiesDocument) RemoveLines(start, end int) {
+	if end > start {
+		d.Lines = append(d.Lines[:start], d.Lines[end:]...)
+	}
+	d.reindex()
+}
+
+// InsertLines inserts lines before the given index and re-indexes the entries
+func (d *PropertiesDocument) InsertLines(at int, lines ...string) {
+	inserted := make([]string, 0, len(d.Lines)+len(lines))
+	inserted = append(inserted, d.Lines[:at]...)
+	inserted = append(inserted, lines...)
+	inserted = append(inserted, d.Lines[at:]...)
+	d.Lines = inserted
+	d.reindex()
+}
+
+// LeadingCommentStart returns the index of the first comment line directly
+// above the entry, or the entry line itself when it has no attached comment
+func (d *PropertiesDocument) LeadingCommentStart(entry *PropertiesEntry) int {
+	start := entry.Line
+	for start > 0 && IsPropertiesComment(d.Lines[start-1]) {
+		start--
+	}
+	return start
+}
+
+// IsPropertiesComment reports whether a line is a properties comment
+func IsPropertiesComment(line string) bool {
+	trimmed := strings.TrimLeft(line, " \t\f")
+	return strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "!")
+}
+
+// EscapePropertiesKey escapes the characters that would otherwise end a properties key
+func EscapePropertiesKey(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		switch r {
+		case '\\', '=', ':', ' ':
+			b.WriteByte('\\')
+		case '\t':
+			b.WriteString(`\t`)
+			continue
+		case '\n':
+			b.WriteString(`\n`)
+			continue
+		case '\r':
+			b.WriteString(`\r`)
+			continue
+		case '\f':
+			b.WriteString(`\f`)
+			continue
+		case '#', '!':
+			if i == 0 {
+				b.WriteByte('\\')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// UnescapePropertiesValue resolves the escape sequences of a raw properties value
+func UnescapePropertiesValue(raw string) string {
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '\\' && i+1 < len(raw) {
+			r, width := unescapePropertiesChar(raw[i+1:])
+			b.WriteRune(r)
+			i += width
+			continue
+		}
+		b.WriteByte(raw[i])
+	}
+	return b.String()
+}
+
+// EscapePropertiesValue escapes a value so that it reads back unchanged
+func EscapePropertiesValue(value string) string {
+	var b strings.Builder
+	for i, r := range value {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case ' ':
+			// Leading whitespace would otherwise be taken as part of the separator
+			if i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// reindex rebuilds the entry list from the current lines
+func (d *PropertiesDocument) reindex() {
+	d.Entries = nil
+	for i := 0; i < len(d.Lines); i++ {
+		line := d.Lines[i]
+		if strings.TrimSpace(line) == "" || IsPropertiesComment(line) {
+			continue
+		}
+
+		entry := parsePropertiesLine(line)
+		entry.Line = i
+
+		// Join continuation lines into the value
+		value := entry.Value
+		end := i + 1
+		for endsWithContinuation(value) && end < len(d.Lines) {
+			value = value[:len(value)-1] + strings.TrimLeft(d.Lines[end], " \t\f")
+			end++
+		}
+		if endsWithContinuation(value) {
+			value = value[:len(value)-1]
+		}
+		entry.Value = value
+		entry.End = end
+		d.Entries = append(d.Entries, entry)
+		i = end - 1
+	}
+}
+
+// parsePropertiesLine splits the first physical line of an entry into key and value
+func parsePropertiesLine(line string) *PropertiesEntry {
+	entry := &PropertiesEntry{}
+
+	i := 0
+	for i < len(line) && isPropertiesWhitespace(line[i]) {
+		i++
+	}
+	entry.KeyStart = i
+
+	var key strings.Builder
+	for i < len(line) {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) {
+			r, width := unescapePropertiesChar(line[i+1:])
+			key.WriteRune(r)
+			i += 1 + width
+			continue
+		}
+		if c == '=' || c == ':' || isPropertiesWhitespace(c) {
+			break
+		}
+		key.WriteByte(c)
+		i++
+	}
+	entry.Key = key.String()
+	entry.KeyEnd = i
+
+	// Separator: optional whitespace, at most one '=' or ':', optional whitespace
+	for i < len(line) && isPropertiesWhitespace(line[i]) {
+		i++
+	}
+	if i < len(line) && (line[i] == '=' || line[i] == ':') {
+		i++
+	}
+	for i < len(line) && isPropertiesWhitespace(line[i]) {
+		i++
+	}
+	entry.ValueStart = i
+	entry.Value = line[i:]
+
+	return entry
+}
+
+// unescapePropertiesChar decodes the escape sequence following a backslash
+func unescapePropertiesChar(s string) (rune, int) {
+	switch s[0] {
+	case 't':
+		return '\t', 1
+	case 'n':
+		return '\n', 1
+	case 'r':
+		return '\r', 1
+	case 'f':
+		return '\f', 1
+	case 'u':
+		if len(s) >= 5 {
+			if code, err := strconv.ParseUint(s[1:5], 16, 16); err == nil {
+				return rune(code), 5
+			}
+		}
+	}
+	return rune(s[0]), 1
+}
+
+// endsWithContinuation reports whether a line ends with an odd number of backslashes
+func endsWithContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+func isPropertiesWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\f'
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// propertiesEntrySummaries describes the entries of a document as key=value@line-end
+func propertiesEntrySummaries(doc *PropertiesDocument) []string {
+	var summaries []string
+	for _, entry := range doc.Entries {
+		summaries = append(summaries, fmt.Sprintf("%s=%s@%d-%d", entry.Key, entry.Value, entry.Line, entry.End))
+	}
+	return summaries
+}
+
+func TestParseProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "separators",
+			content:  "a=1\nb: 2\nc 3\nd\t=\t4\n  e=5\nf=\n",
+			expected: []string{"a=1@0-1", "b=2@1-2", "c=3@2-3", "d=4@3-4", "e=5@4-5", "f=@5-6"},
+		},
+		{
+			name:     "comments and blank lines",
+			content:  "# comment\n! comment\n\na=1 # not a comment\n  # indented comment\nb=2\n",
+			expected: []string{"a=1 # not a comment@3-4", "b=2@5-6"},
+		},
+		{
+			name:     "continuation lines",
+			content:  "a=one, \\\n    two, \\\n    three\nb=2\n",
+			expected: []string{"a=one, two, three@0-3", "b=2@3-4"},
+		},
+		{
+			name:     "escaped backslash is not a continuation",
+			content:  "a=C:\\\\\nb=2\n",
+			expected: []string{`a=C:\\@0-1`, "b=2@1-2"},
+		},
+		{
+			name:     "escaped keys",
+			content:  "a\\=b=1\nkey\\ with\\ spaces=2\n\\#hash=3\nunicode\\u00e9=4\n",
+			expected: []string{"a=b=1@0-1", "key with spaces=2@1-2", "#hash=3@2-3", "unicodeé=4@3-4"},
+		},
+		{
+			name:     "multi-document",
+			content:  "a=1\n#---\nspring.config.activate.on-profile=dev\na=2\n!---\na=3\n",
+			expected: []string{"a=1@0-1", "spring.config.activate.on-profile=dev@2-3", "a=2@3-4", "a=3@5-6"},
+		},
+		{
+			name:     "no trailing newline",
+			content:  "a=1\nb=2",
+			expected: []string{"a=1@0-1", "b=2@1-2"},
+		},
+		{
+			name:     "continuation at end of file",
+			content:  "a=1\\",
+			expected: []string{"a=1@0-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := ParseProperties(tt.content)
+			if actual := doc.String(); actual != tt.content {
+				t.Errorf("String() = %q, want %q", actual, tt.content)
+			}
+			if actual := propertiesEntrySummaries(doc); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("entries = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPropertiesDocumentEdits(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		edit     func(doc *PropertiesDocument)
+		expected string
+	}{
+		{
+			name:    "set value keeps the separator",
+			content: "# server\nserver.port : 8080\nb=2",
+			edit: func(doc *PropertiesDocument) {
+				doc.SetValue(doc.Find("server.port"), "9090")
+			},
+			expected: "# server\nserver.port : 9090\nb=2",
+		},
+		{
+			name:    "set value collapses continuation lines",
+			content: "a=one, \\\n  two\nb=2\n",
+			edit: func(doc *PropertiesDocument) {
+				doc.SetValue(doc.Find("a"), "three")
+			},
+			expected: "a=three\nb=2\n",
+		},
+		{
+			name:    "set key escapes it",
+			content: "old.key = value # kept\n",
+			edit: func(doc *PropertiesDocument) {
+				doc.SetKey(doc.Find("old.key"), "new key")
+			},
+			expected: "new\\ key = value # kept\n",
+		},
+		{
+			name:    "remove and insert lines",
+			content: "a=1\n# about b\nb=2\nc=3",
+			edit: func(doc *PropertiesDocument) {
+				entry := doc.Find("b")
+				doc.RemoveLines(doc.LeadingCommentStart(entry), entry.End)
+				doc.InsertLines(1, "d=4")
+			},
+			expected: "a=1\nd=4\nc=3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := ParseProperties(tt.content)
+			tt.edit(doc)
+			if actual := doc.String(); actual != tt.expected {
+				t.Errorf("String() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPropertiesEscapesRoundTrip(t *testing.T) {
+	values := []string{
+		"plain",
+		" leading space",
+		"trailing space ",
+		`C:\path\to`,
+		"tab\tand\nnewline\r\f",
+		"unicode é ✓",
+		"=:#!",
+		"",
+	}
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			doc := ParseProperties(EscapePropertiesKey(value+".key") + "=" + EscapePropertiesValue(value))
+			if len(doc.Entries) != 1 {
+				t.Fatalf("got %d entries", len(doc.Entries))
+			}
+			entry := doc.Entries[0]
+			if entry.Key != value+".key" {
+				t.Errorf("key = %q, want %q", entry.Key, value+".key")
+			}
+			if actual := UnescapePropertiesValue(entry.Value); actual != value {
+				t.Errorf("value = %q, want %q", actual, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// environmentVariablePattern matches the upper-case form of a property key
+// used in environment variables, e.g. SPRING_DATASOURCE_URL
+var environmentVariablePattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*(?:_+[A-Z0-9]+)+$`)
+
+// MatchKeyGlob reports whether a property key matches a key glob, where "*"
+// matches within a single dot-separated segment, not including a list index,
+// and "**" spans any number of segments. Keys are compared with Spring's relaxed binding rules.
+func MatchKeyGlob(key, pattern string) bool {
+	compiled, err := cachedKeyPattern(pattern)
+	return err == nil && compiled.MatchesExactly(key)
+}
+
+// IsKeyOrSubkey reports whether key equals prefix or lies beneath it, respecting
+// segment boundaries so that "server.port" is not beneath "server.po". Keys are
+// compared with Spring's relaxed binding rules.
+func IsKeyOrSubkey(key, prefix string) bool {
+	keyElements, prefixElements := PropertyKeyElements(key), PropertyKeyElements(prefix)
+	if len(keyElements) < len(prefixElements) {
+		return false
+	}
+	for i, element := range prefixElements {
+		if keyElements[i] != element {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchKeyOrSubkey reports whether key matches the key glob or lies beneath a key that does
+func MatchKeyOrSubkey(key, pattern string) bool {
+	compiled, err := cachedKeyPattern(pattern)
+	return err == nil && compiled.Matches(key)
+}
+
+// EqualKeys reports whether two spellings name the same property under Spring's
+// relaxed binding, e.g. "spring.datasource.driverClassName",
+// "spring.datasource.driver-class-name" and "SPRING_DATASOURCE_DRIVERCLASSNAME"
+func EqualKeys(a, b string) bool {
+	aElements, bElements := PropertyKeyElements(a), PropertyKeyElements(b)
+	if len(aElements) != len(bElements) {
+		return false
+	}
+	for i := range aElements {
+		if aElements[i] != bElements[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// PropertyKeyElements splits a key into the elements Spring compares when
+// binding. Outside brackets, case, dashes and underscores are ignored; bracketed
+// map keys such as "[com.example]" are kept as written, and an index "[0]"
+// equals the segment "0". Environment variable names are split at underscores.
+func PropertyKeyElements(key string) []string {
+	var elements []string
+	if IsEnvironmentVariableName(key) {
+		for _, element := range strings.Split(key, "_") {
+			if element != "" {
+				elements = append(elements, strings.ToLower(element))
+			}
+		}
+		return elements
+	}
+
+	for _, segment := range SplitPropertyKey(key) {
+		name, brackets := segment, ""
+		if i := strings.Index(segment, "["); i >= 0 {
+			name, brackets = segment[:i], segment[i:]
+		}
+		if name != "" {
+			elements = append(elements, uniformKeyElement(name))
+		}
+		for brackets != "" {
+			closing := strings.Index(brackets, "]")
+			if closing < 0 {
+				elements = append(elements, brackets)
+				break
+			}
+			inner := brackets[1:closing]
+			if isDigits(inner) {
+				elements = append(elements, inner)
+			} else {
+				elements = append(elements, "["+inner+"]")
+			}
+			brackets = brackets[closing+1:]
+		}
+	}
+	return elements
+}
+
+// IsEnvironmentVariableName reports whether key is written in the upper-case,
+// underscore-separated form Spring maps from environment variables
+func IsEnvironmentVariableName(key string) bool {
+	return environmentVariablePattern.MatchString(key)
+}
+
+// ToEnvironmentVariableName converts a property key to the environment variable
+// Spring binds to it, e.g. "spring.datasource.driver-class-name" to
+// SPRING_DATASOURCE_DRIVERCLASSNAME and "my.list[0]" to MY_LIST_0
+func ToEnvironmentVariableName(key string) string {
+	if IsEnvironmentVariableName(key) {
+		return key
+	}
+	var elements []string
+	for _, element := range PropertyKeyElements(key) {
+		element = strings.Trim(element, "[]")
+		element = strings.NewReplacer(".", "_", "-", "").Replace(element)
+		elements = append(elements, strings.ToUpper(element))
+	}
+	return strings.Join(elements, "_")
+}
+
+// uniformKeyElement lower-cases an element and drops the dashes and underscores
+// that relaxed binding ignores
+func uniformKeyElement(element string) string {
+	var b strings.Builder
+	for _, r := range element {
+		if r == '-' || r == '_' {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MapValuedProperties are Spring properties bound to maps, whose keys are
+// user-defined names (logger names, Hibernate settings, ...) rather than
+// property segments, and must therefore be kept exactly as written
+var MapValuedProperties = []string{
+	"logging.level",
+	"logging.group",
+	"spring.jpa.properties",
+	"spring.datasource.hikari.data-source-properties",
+	"spring.kafka.properties",
+	"spring.kafka.admin.properties",
+	"spring.kafka.consumer.properties",
+	"spring.kafka.producer.properties",
+	"spring.kafka.streams.properties",
+	"spring.jackson.serialization",
+	"spring.jackson.deserialization",
+	"spring.jackson.mapper",
+	"spring.jackson.parser",
+	"spring.jackson.generator",
+	"spring.security.oauth2.client.registration",
+	"spring.security.oauth2.client.provider",
+	"spring.cloud.stream.bindings",
+	"spring.cloud.gateway.metrics.tags",
+	"management.metrics.tags",
+	"management.metrics.enable",
+	"management.metrics.distribution.percentiles-histogram",
+	"management.metrics.distribution.percentiles",
+	"management.metrics.distribution.slo",
+	"info",
+}
+
+// IsMapValuedKey reports whether a key lies beneath one of the MapValuedProperties,
+// meaning the part after that property is a user-defined map key
+func IsMapValuedKey(key string) bool {
+	for _, property := range MapValuedProperties {
+		if IsKeyOrSubkey(key, property) && !EqualKeys(key, property) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitPropertyKey splits a dotted key into segments, keeping bracketed parts
+// such as "[com.example]" or "[0]" attached to the segment they follow
+func SplitPropertyKey(key string) []string {
+	var segments []string
+	depth, start := 0, 0
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case '.':
+			if depth == 0 {
+				segments = append(segments, key[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, key[start:])
+}
+
+// KebabCaseKey converts every segment of key to kebab-case, the canonical form
+// of Spring property names. prefix is the canonical key the segments are nested
+// under, if any. Bracketed map keys and the user-defined keys of map-valued
+// properties are left exactly as written.
+func KebabCaseKey(prefix, key string) string {
+	segments := SplitPropertyKey(key)
+	for i, segment := range segments {
+		if IsMapValuedProperty(prefix) || IsMapValuedKey(prefix) {
+			break
+		}
+		segments[i] = kebabCaseSegment(segment)
+		prefix = JoinYAMLPath(prefix, segments[i])
+	}
+	return strings.Join(segments, ".")
+}
+
+// IsMapValuedProperty reports whether the key is itself one of the MapValuedProperties
+func IsMapValuedProperty(key string) bool {
+	for _, property := range MapValuedProperties {
+		if EqualKeys(key, property) {
+			return true
+		}
+	}
+	return false
+}
+
+// kebabCaseSegment converts camelCase and snake_case in a single key segment to kebab-case
+func kebabCaseSegment(segment string) string {
+	name, index := segment, ""
+	if i := strings.Index(segment, "["); i >= 0 {
+		name, index = segment[:i], segment[i:]
+	}
+	if strings.ToLower(name) == name && !strings.Contains(name, "_") {
+		return segment
+	}
+
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if (unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower)) &&
+				!strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSuffix(b.String(), "-") + index
+}
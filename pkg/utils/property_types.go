@@ -0,0 +1,435 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Value formats Spring Boot converts property values from, following
+// DurationStyle, PeriodStyle and DataSize
+var (
+	simpleUnitPattern  = regexp.MustCompile(`^[+-]?\d+([a-zA-Z]{0,2})$`)
+	isoDurationPattern = regexp.MustCompile(`^[+-]?[pP](?:[+-]?\d+[dD])?(?:[tT](?:[+-]?\d+[hH])?(?:[+-]?\d+[mM])?(?:[+-]?\d+(?:[.,]\d{0,9})?[sS])?)?$`)
+	isoPeriodPattern   = regexp.MustCompile(`^[+-]?[pP](?:[+-]?\d+[yY])?(?:[+-]?\d+[mM])?(?:[+-]?\d+[wW])?(?:[+-]?\d+[dD])?$`)
+)
+
+var (
+	durationUnits = map[string]bool{"": true, "ns": true, "us": true, "ms": true, "s": true, "m": true, "h": true, "d": true}
+	periodUnits   = map[string]bool{"": true, "y": true, "m": true, "w": true, "d": true}
+	dataSizeUnits = map[string]bool{"": true, "b": true, "kb": true, "mb": true, "gb": true, "tb": true}
+)
+
+// integerBits are the sizes of the Java integer types, 0 for BigInteger
+var integerBits = map[string]int{
+	"byte": 8, "java.lang.Byte": 8,
+	"short": 16, "java.lang.Short": 16,
+	"int": 32, "java.lang.Integer": 32,
+	"long": 64, "java.lang.Long": 64,
+	"java.math.BigInteger": 0,
+}
+
+// floatTypes are the Java floating point and decimal types
+var floatTypes = map[string]bool{
+	"float": true, "java.lang.Float": true,
+	"double": true, "java.lang.Double": true,
+	"java.math.BigDecimal": true,
+}
+
+// simpleTypes are the other Java types bound from a single value; no key below
+// a property of these types binds to it
+var simpleTypes = map[string]bool{
+	"boolean":                                true,
+	"java.lang.Boolean":                      true,
+	"char":                                   true,
+	"java.lang.Character":                    true,
+	"java.lang.String":                       true,
+	"java.lang.Class":                        true,
+	"java.time.Duration":                     true,
+	"java.time.Period":                       true,
+	"org.springframework.util.unit.DataSize": true,
+	"java.nio.charset.Charset":               true,
+	"java.util.Locale":                       true,
+	"java.util.TimeZone":                     true,
+	"java.io.File":                           true,
+	"java.nio.file.Path":                     true,
+	"java.net.URI":                           true,
+	"java.net.URL":                           true,
+	"java.net.InetAddress":                   true,
+	"java.util.regex.Pattern":                true,
+	"org.springframework.core.io.Resource":   true,
+	"org.springframework.util.MimeType":      true,
+}
+
+// MetadataIndex looks up configuration metadata by property key, comparing
+// keys with Spring's relaxed binding rules
+type MetadataIndex struct {
+	properties map[string]*ConfigurationProperty
+	hints      map[string]*ConfigurationHint
+	enums      map[string][]string
+	namespaces map[string]bool
+}
+
+// PropertyBinding is the metadata of the property a key binds to
+type PropertyBinding struct {
+	Property *ConfigurationProperty
+	// ValueType is the Java type of the value at the key, which differs from the
+	// property type for the elements of lists and the values of maps; empty when unknown
+	ValueType string
+	// Hint documents the values at the key, if any
+	Hint *ConfigurationHint
+
+	enums map[string][]string
+}
+
+// NewMetadataIndex indexes the properties and hints of the metadata; the
+// first definition of a property wins
+func NewMetadataIndex(metadata *ConfigurationMetadata) *MetadataIndex {
+	index := &MetadataIndex{
+		properties: make(map[string]*ConfigurationProperty),
+		hints:      make(map[string]*ConfigurationHint),
+		enums:      metadata.Enums,
+		namespaces: make(map[string]bool),
+	}
+	for _, property := range metadata.Properties {
+		key := uniformKey(property.Name)
+		if _, ok := index.properties[key]; !ok {
+			index.properties[key] = property
+		}
+		index.addNamespace(property.Name)
+	}
+	for _, group := range metadata.Groups {
+		index.addNamespace(group.Name)
+	}
+	for _, hint := range metadata.Hints {
+		key := uniformKey(hint.Name)
+		if _, ok := index.hints[key]; !ok {
+			index.hints[key] = hint
+		}
+	}
+	return index
+}
+
+// addNamespace records the first element of a key
+func (i *MetadataIndex) addNamespace(key string) {
+	if elements := PropertyKeyElements(key); len(elements) > 0 {
+		i.namespaces[elements[0]] = true
+	}
+}
+
+// InNamespace reports whether the first element of key is that of a property
+// or group of the metadata, such as "spring" or "server"
+func (i *MetadataIndex) InNamespace(key string) bool {
+	elements := PropertyKeyElements(key)
+	return len(elements) > 0 && i.namespaces[elements[0]]
+}
+
+// Resolve returns the property a key binds to: the property itself, an element
+// of a list property, a key of a map property, or a key beneath a property of
+// a type the metadata does not describe further
+func (i *MetadataIndex) Resolve(key string) (*PropertyBinding, bool) {
+	elements := PropertyKeyElements(key)
+	for n := len(elements); n > 0; n-- {
+		name := strings.Join(elements[:n], ".")
+		property := i.properties[name]
+		if property == nil {
+			continue
+		}
+		if n == len(elements) {
+			return &PropertyBinding{Property: property, ValueType: property.Type, Hint: i.hints[name], enums: i.enums}, true
+		}
+
+		valueType, hintName, ok := nestedValueType(property.Type, elements[n:])
+		if !ok {
+			return nil, false
+		}
+		binding := &PropertyBinding{Property: property, ValueType: valueType, enums: i.enums}
+		switch hintName {
+		case "values":
+			binding.Hint = i.hints[name+".values"]
+		case "elements":
+			binding.Hint = i.hints[name]
+		}
+		return binding, true
+	}
+	return nil, false
+}
+
+// nestedValueType returns the type of the value at the elements below a
+// property of the given type, and which hints of the property apply to it
+func nestedValueType(javaType string, elements []string) (string, string, bool) {
+	raw, args := ParseJavaType(javaType)
+	switch {
+	case isCollectionType(raw):
+		if !isDigits(elements[0]) {
+			return "", "", false
+		}
+		elementType := strings.TrimSuffix(raw, "[]")
+		if len(args) > 0 {
+			elementType = args[0]
+		}
+		if len(elements) == 1 {
+			return elementType, "elements", true
+		}
+		valueType, _, ok := nestedValueType(elementType, elements[1:])
+		return valueType, "", ok
+	case isMapType(raw):
+		valueType := "java.lang.String"
+		if len(args) > 1 {
+			valueType = args[1]
+		}
+		// Keys of a map of values may contain dots, so they take all remaining elements
+		valueRaw, _ := ParseJavaType(valueType)
+		if isCollectionType(valueRaw) || isMapType(valueRaw) {
+			return "", "", true
+		}
+		return valueType, "values", true
+	case javaType == "" || !isValueType(javaType):
+		// Nested objects are not described by the metadata
+		return "", "", true
+	default:
+		return "", "", false
+	}
+}
+
+// ParseJavaType splits a Java type such as "java.util.Map<java.lang.String,java.lang.Integer>"
+// into its raw type and type arguments
+func ParseJavaType(javaType string) (string, []string) {
+	javaType = strings.TrimSpace(javaType)
+	open := strings.Index(javaType, "<")
+	if open < 0 || !strings.HasSuffix(javaType, ">") {
+		return javaType, nil
+	}
+	var args []string
+	depth, start := 0, open+1
+	for i := open + 1; i < len(javaType)-1; i++ {
+		switch javaType[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(javaType[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	args = append(args, strings.TrimSpace(javaType[start:len(javaType)-1]))
+	return javaType[:open], args
+}
+
+// isCollectionType reports whether a raw Java type is bound from indexed or comma-separated values
+func isCollectionType(raw string) bool {
+	switch raw {
+	case "java.util.List", "java.util.Set", "java.util.Collection", "java.util.SortedSet",
+		"java.util.ArrayList", "java.util.LinkedList", "java.util.HashSet", "java.util.LinkedHashSet",
+		"java.util.TreeSet", "java.lang.Iterable":
+		return true
+	}
+	return strings.HasSuffix(raw, "[]")
+}
+
+// isMapType reports whether a raw Java type is bound from keyed values
+func isMapType(raw string) bool {
+	switch raw {
+	case "java.util.Map", "java.util.HashMap", "java.util.LinkedHashMap", "java.util.TreeMap",
+		"java.util.SortedMap", "java.util.Properties":
+		return true
+	}
+	return false
+}
+
+// isValueType reports whether a Java type is one of the known types bound from a single value
+func isValueType(javaType string) bool {
+	raw, _ := ParseJavaType(javaType)
+	_, ok := integerBits[raw]
+	return ok || floatTypes[raw] || simpleTypes[raw]
+}
+
+// ValidateValue reports a value that cannot be converted to the type of the
+// binding, such as "eighty" for an Integer, or that is not a constant of an
+// enum, read from its class or taken from the values the metadata documents.
+// Placeholders and empty values are not checked.
+func (b *PropertyBinding) ValidateValue(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.Contains(value, "${") || strings.Contains(value, "#{") {
+		return nil
+	}
+
+	raw, args := ParseJavaType(b.ValueType)
+	if isCollectionType(raw) {
+		// A single value is split at commas into the elements
+		elementType := strings.TrimSuffix(raw, "[]")
+		if len(args) > 0 {
+			elementType = args[0]
+		}
+		for _, element := range strings.Split(value, ",") {
+			if err := b.validateScalar(elementType, strings.TrimSpace(element)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if isMapType(raw) {
+		return nil
+	}
+	return b.validateScalar(b.ValueType, value)
+}
+
+// validateScalar reports a single value that cannot be converted to a Java type
+func (b *PropertyBinding) validateScalar(javaType, value string) error {
+	if value == "" {
+		return nil
+	}
+	raw, _ := ParseJavaType(javaType)
+	if bits, ok := integerBits[raw]; ok {
+		if !isJavaInteger(value, bits) {
+			return fmt.Errorf("%q is not a valid %s", value, javaType)
+		}
+		return nil
+	}
+
+	valid := true
+	switch {
+	case floatTypes[raw]:
+		_, err := strconv.ParseFloat(strings.TrimRight(value, "dDfF"), 64)
+		valid = err == nil
+	case raw == "boolean" || raw == "java.lang.Boolean":
+		switch strings.ToLower(value) {
+		case "true", "false", "on", "off", "yes", "no", "1", "0":
+		default:
+			valid = false
+		}
+	case raw == "char" || raw == "java.lang.Character":
+		valid = len([]rune(value)) == 1
+	case raw == "java.time.Duration":
+		valid = hasSimpleUnit(value, durationUnits) || isISO8601(value, isoDurationPattern)
+	case raw == "java.time.Period":
+		valid = hasSimpleUnit(value, periodUnits) || isISO8601(value, isoPeriodPattern)
+	case raw == "org.springframework.util.unit.DataSize":
+		valid = hasSimpleUnit(value, dataSizeUnits)
+	case b.enums[raw] != nil:
+		if !isEnumConstant(value, b.enums[raw]) {
+			return fmt.Errorf("%q is not a valid %s, expected one of: %s", value, javaType, strings.Join(b.enums[raw], ", "))
+		}
+	}
+	if !valid {
+		return fmt.Errorf("%q is not a valid %s", value, javaType)
+	}
+	return nil
+}
+
+// isEnumConstant reports whether a value binds to one of the constants of an
+// enum, which Spring compares ignoring case, dashes and underscores
+func isEnumConstant(value string, constants []string) bool {
+	value = uniformKeyElement(value)
+	for _, constant := range constants {
+		if uniformKeyElement(constant) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// DocumentedValues returns the values the hint of the binding documents
+func (b *PropertyBinding) DocumentedValues() []string {
+	if b.Hint == nil {
+		return nil
+	}
+	values := make([]string, 0, len(b.Hint.Values))
+	for _, value := range b.Hint.Values {
+		values = append(values, fmt.Sprint(value.Value))
+	}
+	return values
+}
+
+// IsDocumentedValue reports whether a value, or every element of a list, is
+// one of the documented values; values of properties without documented
+// values, or whose hint allows any value, always are. Values are compared like
+// enum constants, ignoring case, dashes and underscores.
+func (b *PropertyBinding) IsDocumentedValue(value string) bool {
+	values := b.DocumentedValues()
+	if len(values) == 0 || b.acceptsAnyValue() || strings.Contains(value, "${") {
+		return true
+	}
+	raw, _ := ParseJavaType(b.ValueType)
+	elements := []string{value}
+	if isCollectionType(raw) {
+		elements = strings.Split(value, ",")
+	}
+	for _, element := range elements {
+		element = uniformKeyElement(strings.TrimSpace(element))
+		if element == "" {
+			continue
+		}
+		found := false
+		for _, documented := range values {
+			if uniformKeyElement(documented) == element {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptsAnyValue reports whether the hint of the binding allows values besides the documented ones
+func (b *PropertyBinding) acceptsAnyValue() bool {
+	for _, provider := range b.Hint.Providers {
+		if provider.Name == "any" {
+			return true
+		}
+	}
+	return false
+}
+
+// isJavaInteger reports whether a value converts to a Java integer of the
+// given size, 0 for BigInteger: a decimal number, or a hexadecimal one
+// prefixed with "0x" or "#"
+func isJavaInteger(value string, bits int) bool {
+	digits, base := value, 10
+	sign := ""
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	switch {
+	case strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X"):
+		digits, base = digits[2:], 16
+	case strings.HasPrefix(digits, "#"):
+		digits, base = digits[1:], 16
+	}
+	if digits == "" || strings.ContainsAny(digits, "+-_") {
+		return false
+	}
+	if bits == 0 {
+		_, ok := new(big.Int).SetString(sign+digits, base)
+		return ok
+	}
+	if sign == "+" {
+		sign = ""
+	}
+	_, err := strconv.ParseInt(sign+digits, base, bits)
+	return err == nil
+}
+
+// hasSimpleUnit reports whether a value is a number with one of the given unit suffixes
+func hasSimpleUnit(value string, units map[string]bool) bool {
+	match := simpleUnitPattern.FindStringSubmatch(value)
+	return match != nil && units[strings.ToLower(match[1])]
+}
+
+// isISO8601 reports whether a value is an ISO-8601 duration or period with at least one component
+func isISO8601(value string, pattern *regexp.Regexp) bool {
+	return pattern.MatchString(value) && strings.ContainsAny(value, "0123456789")
+}
+
+// uniformKey returns the elements of a key that relaxed binding compares, joined by dots
+func uniformKey(key string) string {
+	return strings.Join(PropertyKeyElements(key), ".")
+}
@@ -0,0 +1,34 @@
+package utils
+
+import "testing"
+
+func TestValidateValueOfUnloadedEnum(t *testing.T) {
+	metadata := &ConfigurationMetadata{
+		Properties: []*ConfigurationProperty{{Name: "app.mode", Type: "com.example.Mode"}},
+		Hints: []*ConfigurationHint{{
+			Name:   "app.mode",
+			Values: []HintValue{{Value: "fast"}, {Value: "safe"}},
+		}},
+	}
+	binding, ok := NewMetadataIndex(metadata).Resolve("app.mode")
+	if !ok {
+		t.Fatal("app.mode is not resolved")
+	}
+
+	// Hints document some of the values, so others are not invalid
+	if err := binding.ValidateValue("balanced"); err != nil {
+		t.Errorf("ValidateValue(\"balanced\") error = %v", err)
+	}
+	if binding.IsDocumentedValue("balanced") {
+		t.Error("IsDocumentedValue(\"balanced\") = true")
+	}
+	if !binding.IsDocumentedValue("fast") {
+		t.Error("IsDocumentedValue(\"fast\") = false")
+	}
+
+	metadata.Enums = map[string][]string{"com.example.Mode": {"FAST", "SAFE"}}
+	binding, _ = NewMetadataIndex(metadata).Resolve("app.mode")
+	if err := binding.ValidateValue("balanced"); err == nil {
+		t.Error("ValidateValue(\"balanced\") error = nil with the enum constants loaded")
+	}
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// secretKeySuffixes end the names of keys that hold secrets, compared without
+// case, dashes or underscores, e.g. "spring.datasource.password",
+// "client-secret" or API_KEY
+var secretKeySuffixes = []string{
+	"password", "passwd", "pwd", "passphrase", "secret", "token", "credentials",
+	"apikey", "accesskey", "secretkey", "privatekey", "signingkey", "encryptionkey",
+}
+
+var (
+	// secretTokenPattern matches values made of the characters of generated
+	// keys and tokens: base64, base64url and hexadecimal
+	secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9+/=_-]+$`)
+	uuidPattern        = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// Thresholds above which a value of any key is taken for a generated secret
+const (
+	minSecretTokenLength = 16
+	minSecretEntropy     = 3.5 // bits per character
+)
+
+// IsSecretKey reports whether the name of a property key or an environment
+// variable suggests that it holds a secret
+func IsSecretKey(key string) bool {
+	var name string
+	if IsEnvironmentVariableName(key) {
+		name = strings.ToLower(strings.ReplaceAll(key, "_", ""))
+	} else if elements := PropertyKeyElements(key); len(elements) > 0 {
+		name = strings.Trim(elements[len(elements)-1], "[]")
+		name = uniformKeyElement(name)
+	}
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLikelySecret reports whether a literal value set for a key looks like a
+// secret: any value of a key named like a secret, or a value of any other key
+// that looks like a generated key or token. Values Spring Cloud Config
+// decrypts ("{cipher}...") and resource locations are not secrets themselves,
+// nor are booleans, numbers and values made of placeholders only, which
+// switches and settings such as "app.use-token" hold.
+func IsLikelySecret(key, value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.HasPrefix(value, "{cipher}") {
+		return false
+	}
+	if isBooleanValue(value) || isNumeric(value) || isPlaceholderOnly(value) {
+		return false
+	}
+	for _, prefix := range []string{"classpath:", "classpath*:", "file:", "http:", "https:"} {
+		if strings.HasPrefix(value, prefix) {
+			return false
+		}
+	}
+	if IsSecretKey(key) {
+		return true
+	}
+	return IsHighEntropyToken(value)
+}
+
+// isBooleanValue reports whether a value converts to a boolean property
+func isBooleanValue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "false", "on", "off", "yes", "no":
+		return true
+	}
+	return false
+}
+
+// isPlaceholderOnly reports whether a value holds nothing but placeholders
+func isPlaceholderOnly(value string) bool {
+	placeholders := ParsePlaceholders(value)
+	if len(placeholders) == 0 {
+		return false
+	}
+	var rest strings.Builder
+	last := 0
+	for _, placeholder := range placeholders {
+		rest.WriteString(value[last:placeholder.Start])
+		last = placeholder.End
+	}
+	rest.WriteString(value[last:])
+	return strings.TrimSpace(rest.String()) == ""
+}
+
+// IsHighEntropyToken reports whether a value looks randomly generated: a long
+// run of base64 or hexadecimal characters mixing letters and digits, with a
+// high Shannon entropy. UUIDs are left out since they mostly identify things.
+func IsHighEntropyToken(value string) bool {
+	if len(value) < minSecretTokenLength || !secretTokenPattern.MatchString(value) || uuidPattern.MatchString(value) {
+		return false
+	}
+	if !strings.ContainsAny(value, "0123456789") || strings.Trim(value, "0123456789+/=_-") == "" {
+		return false
+	}
+	return ShannonEntropy(value) >= minSecretEntropy
+}
+
+// ShannonEntropy returns the entropy of the characters of a value in bits per character
+func ShannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
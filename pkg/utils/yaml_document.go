@@ -0,0 +1,604 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// YAMLEntry is a mapping key or block sequence item found while scanning a
+// YAML file line by line. Items are keyed by their index, e.g. "[0]", and span
+// the dash; a mapping that starts on the line of its item's dash is its child.
+type YAMLEntry struct {
+	Key        string // key as written, without quotes
+	Path       string // full dotted property path from the document root, e.g. "a.list[0].name"
+	Value      string // inline scalar value without quotes, empty for nested mappings
+	RawValue   string // inline value as written, without the trailing comment
+	Line       int    // index of the line holding the key
+	End        int    // one past the last content line of the entry's subtree
+	Indent     int    // column of the key
+	KeyStart   int    // offset of the key (including quotes) in Lines[Line]
+	KeyEnd     int    // offset just past the key (including quotes) in Lines[Line]
+	ValueStart int    // offset of RawValue in Lines[Line]
+	Document   int    // index of the document in a multi-document file
+	Item       bool   // whether the entry is a sequence item
+	Parent     *YAMLEntry
+	Children   []*YAMLEntry
+}
+
+// HasValue reports whether the entry holds an inline scalar rather than a nested block
+func (e *YAMLEntry) HasValue() bool {
+	return e.RawValue != "" && !isBlockScalarIndicator(e.RawValue)
+}
+
+// IsBlockScalar reports whether the entry value is a literal or folded block scalar
+func (e *YAMLEntry) IsBlockScalar() bool {
+	return isBlockScalarIndicator(e.RawValue)
+}
+
+// IsSequence reports whether the entry holds a block sequence
+func (e *YAMLEntry) IsSequence() bool {
+	return len(e.Children) > 0 && e.Children[0].Item
+}
+
+// SharesItemLine reports whether the entry starts on the line of the dash of
+// its parent sequence item, as "name" does in "- name: value"
+func (e *YAMLEntry) SharesItemLine() bool {
+	return e.Parent != nil && e.Parent.Item && e.Parent.Line == e.Line
+}
+
+// YAMLDocument is a line-oriented view of a YAML file that keeps comments and
+// formatting intact while resolving the full property path of every key.
+// Multi-document files are supported; each entry records its document index.
+type YAMLDocument struct {
+	Lines   []string
+	Entries []*YAMLEntry
+	// DocumentStarts holds the first line of each document, after its "---" separator
+	DocumentStarts []int
+}
+
+// ParseYAML parses "\n"-separated YAML content into a document
+func ParseYAML(content string) *YAMLDocument {
+	doc := &YAMLDocument{Lines: strings.Split(content, "\n")}
+	doc.reindex()
+	return doc
+}
+
+// String joins the document lines back into file content
+func (d *YAMLDocument) String() string {
+	return strings.Join(d.Lines, "\n")
+}
+
+// Find returns the first entry in any document with exactly the given path
+func (d *YAMLDocument) Find(path string) *YAMLEntry {
+	for _, entry := range d.Entries {
+		if entry.Path == path {
+			return entry
+		}
+	}
+	return nil
+}
+
+// FindInDocument returns the entry with the given path inside one document
+func (d *YAMLDocument) FindInDocument(path string, document int) *YAMLEntry {
+	for _, entry := range d.Entries {
+		if entry.Document == document && entry.Path == path {
+			return entry
+		}
+	}
+	return nil
+}
+
+// DocumentCount returns the number of documents in the file
+func (d *YAMLDocument) DocumentCount() int {
+	return len(d.DocumentStarts)
+}
+
+// DocumentEnd returns one past the last line of the given document
+func (d *YAMLDocument) DocumentEnd(document int) int {
+	if document+1 < len(d.DocumentStarts) {
+		// The next document starts after its separator line
+		return d.DocumentStarts[document+1] - 1
+	}
+	return len(d.Lines)
+}
+
+// SetLines replaces all lines of the document and re-indexes the entries
+func (d *YAMLDocument) SetLines(lines []string) {
+	d.Lines = lines
+	d.reindex()
+}
+
+// SetValue replaces the inline value of an entry, preserving any trailing comment
+func (d *YAMLDocument) SetValue(entry *YAMLEntry, rawValue string) {
+	line := d.Lines[entry.Line]
+	rest := line[entry.ValueStart+len(entry.RawValue):]
+	if entry.RawValue == "" {
+		// Key without value: "key:" becomes "key: value"
+		if rest != "" {
+			rest = " " + rest
+		}
+		d.Lines[entry.Line] = line[:entry.KeyEnd+1] + " " + rawValue + rest
+	} else {
+		d.Lines[entry.Line] = line[:entry.ValueStart] + rawValue + rest
+	}
+	d.reindex()
+}
+
+// YAMLFlowItem is a scalar item of a flow sequence such as "[a, b]"
+type YAMLFlowItem struct {
+	Value string // value without quotes
+	Raw   string // item as written
+	Start int    // offset of Raw in the raw value of the sequence
+	End   int    // offset just past Raw
+}
+
+// FlowSequenceItems returns the items of an entry whose inline value is a
+// flow sequence of scalars, or false when the value is anything else, such as
+// a flow sequence holding collections
+func (e *YAMLEntry) FlowSequenceItems() ([]YAMLFlowItem, bool) {
+	raw := e.RawValue
+	if len(raw) < 2 || raw[0] != '[' || raw[len(raw)-1] != ']' {
+		return nil, false
+	}
+	p := &flowParser{text: raw, pos: 1}
+	var items []YAMLFlowItem
+	for {
+		p.skipSpaces()
+		if p.consume(']') {
+			return items, p.pos == len(raw)
+		}
+		if c := raw[p.pos]; c == '[' || c == '{' {
+			return nil, false
+		}
+		start := p.pos
+		item := p.scalar(false)
+		if item == "" || strings.Contains(item, ": ") {
+			return nil, false
+		}
+		items = append(items, YAMLFlowItem{Value: ResolveYAMLScalar(item), Raw: item, Start: start, End: start + len(item)})
+		if err := p.separator(']'); err != nil {
+			return nil, false
+		}
+	}
+}
+
+// FormatYAMLFlowSequence renders raw items as a flow sequence, keeping the
+// spacing and separators of the sequence previousRaw holding the previous items
+func FormatYAMLFlowSequence(items []string, previous []YAMLFlowItem, previousRaw string) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+	open, close, separator := "", "", ", "
+	if len(previous) > 0 {
+		open = previousRaw[1:previous[0].Start]
+		// A trailing comma is dropped
+		close = strings.Replace(previousRaw[previous[len(previous)-1].End:len(previousRaw)-1], ",", "", 1)
+	}
+	if len(previous) > 1 {
+		separator = previousRaw[previous[0].End:previous[1].Start]
+	}
+	return "[" + open + strings.Join(items, separator) + close + "]"
+}
+
+// FormatYAMLFlowScalar renders a value as a scalar inside a flow collection,
+// where commas and brackets also need quotes
+func FormatYAMLFlowScalar(value, previousRaw string) string {
+	formatted := FormatYAMLScalar(value, previousRaw)
+	if formatted == value && strings.ContainsAny(value, ",[]{}") {
+		return QuoteYAMLScalar(value, '"')
+	}
+	return formatted
+}
+
+// SetKey replaces the key text of an entry
+func (d *YAMLDocument) SetKey(entry *YAMLEntry, key string) {
+	line := d.Lines[entry.Line]
+	d.Lines[entry.Line] = line[:entry.KeyStart] + FormatYAMLKey(key) + line[entry.KeyEnd:]
+	d.reindex()
+}
+
+// RemoveLines deletes the lines in [start, end) and re-indexes the entries
+func (d *YAMLDocument) RemoveLines(start, end int) {
+	if end > start {
+		d.Lines = append(d.Lines[:start], d.Lines[end:]...)
+	}
+	d.reindex()
+}
+
+// InsertLines inserts lines before the given index and re-indexes the entries
+func (d *YAMLDocument) InsertLines(at int, lines ...string) {
+	inserted := make([]string, 0, len(d.Lines)+len(lines))
+	inserted = append(inserted, d.Lines[:at]...)
+	inserted = append(inserted, lines...)
+	inserted = append(inserted, d.Lines[at:]...)
+	d.Lines = inserted
+	d.reindex()
+}
+
+// LeadingCommentStart returns the index of the first comment line directly
+// above the entry, or the entry line itself when it has no attached comment
+func (d *YAMLDocument) LeadingCommentStart(entry *YAMLEntry) int {
+	start := entry.Line
+	for start > 0 && isYAMLComment(d.Lines[start-1]) {
+		start--
+	}
+	return start
+}
+
+// IndentUnit returns the indentation step used by the file, defaulting to two spaces
+func (d *YAMLDocument) IndentUnit() int {
+	unit := 0
+	for _, entry := range d.Entries {
+		// The dash of a sequence item does not count as indentation
+		if entry.Parent != nil && !entry.Item && !entry.Parent.Item {
+			step := entry.Indent - entry.Parent.Indent
+			if step > 0 && (unit == 0 || step < unit) {
+				unit = step
+			}
+		}
+	}
+	if unit == 0 {
+		return 2
+	}
+	return unit
+}
+
+// JoinYAMLPath appends a key to a dotted property path; bracketed map keys
+// such as "[com.example]" are appended without a separating dot
+func JoinYAMLPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	if strings.HasPrefix(key, "[") {
+		return parent + key
+	}
+	return parent + "." + key
+}
+
+// FormatYAMLKey quotes a key when it cannot be written as a plain scalar
+func FormatYAMLKey(key string) string {
+	if strings.HasPrefix(key, "[") || strings.ContainsAny(key, ":#{}&*!|>'\"%@`") || ResolveYAMLScalar(key) != key {
+		return "\"" + strings.ReplaceAll(key, "\"", "\\\"") + "\""
+	}
+	return key
+}
+
+// reindex rebuilds the entry list from the current lines
+func (d *YAMLDocument) reindex() {
+	d.Entries = nil
+	d.DocumentStarts = []int{0}
+
+	var stack []*YAMLEntry
+	document := 0
+	lastContent := -1
+	var blockOwner *YAMLEntry
+
+	closeEntries := func(keep func(*YAMLEntry) bool) {
+		for len(stack) > 0 && !keep(stack[len(stack)-1]) {
+			stack[len(stack)-1].End = lastContent + 1
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	for i, line := range d.Lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		// Block scalar content belongs to its key regardless of what it looks like
+		if blockOwner != nil {
+			if trimmed == "" {
+				continue
+			}
+			if indent > blockOwner.Indent {
+				lastContent = i
+				continue
+			}
+			blockOwner = nil
+		}
+
+		if trimmed == "" || isYAMLComment(line) {
+			continue
+		}
+
+		if indent == 0 && (trimmed == "---" || strings.HasPrefix(trimmed, "--- ") || trimmed == "...") {
+			closeEntries(func(*YAMLEntry) bool { return false })
+			switch {
+			case trimmed == "...":
+			case lastContent < 0:
+				// A leading separator opens the first document rather than a second one
+				d.DocumentStarts[0] = i + 1
+			default:
+				document++
+				d.DocumentStarts = append(d.DocumentStarts, i+1)
+			}
+			continue
+		}
+
+		// An item closes its previous sibling but stays below the key that owns
+		// the sequence, even when the dash is not indented beneath it
+		isItem := isYAMLSequenceItem(trimmed)
+		closeEntries(func(e *YAMLEntry) bool {
+			return e.Indent < indent || (e.Indent == indent && isItem && !e.Item && e.RawValue == "")
+		})
+		lastContent = i
+
+		// Each dash opens an item; "- - a" nests one sequence in another
+		column := indent
+		var item *YAMLEntry
+		for isYAMLSequenceItem(line[column:]) {
+			item = &YAMLEntry{Indent: column, KeyStart: column, KeyEnd: column + 1, Line: i, Document: document, Item: true}
+			var parent *YAMLEntry
+			siblings := d.Entries
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+				siblings = parent.Children
+			}
+			index := 0
+			for _, sibling := range siblings {
+				if sibling.Item && sibling.Parent == parent && sibling.Document == document {
+					index++
+				}
+			}
+			item.Key = "[" + strconv.Itoa(index) + "]"
+			d.addEntry(item, stack)
+			stack = append(stack, item)
+
+			column++
+			for column < len(line) && line[column] == ' ' {
+				column++
+			}
+		}
+
+		rest := line[column:]
+		if item != nil && (rest == "" || isYAMLComment(rest)) {
+			continue
+		}
+		var entry *YAMLEntry
+		if item == nil || (rest[0] != '[' && rest[0] != '{') {
+			entry = parseYAMLLine(strings.Repeat(" ", column) + rest)
+		}
+		if entry == nil {
+			if item != nil {
+				// A scalar item
+				item.RawValue = stripYAMLComment(rest)
+				item.ValueStart = column
+				if item.HasValue() {
+					item.Value = UnquoteYAMLScalar(item.RawValue)
+				}
+				if item.IsBlockScalar() {
+					blockOwner = item
+				}
+			}
+			// Otherwise a continuation line of the enclosing entry
+			continue
+		}
+
+		entry.Line = i
+		entry.Document = document
+		d.addEntry(entry, stack)
+		stack = append(stack, entry)
+
+		if entry.IsBlockScalar() {
+			blockOwner = entry
+		}
+	}
+
+	closeEntries(func(*YAMLEntry) bool { return false })
+}
+
+// addEntry appends an entry to the document as a child of the top of stack
+func (d *YAMLDocument) addEntry(entry *YAMLEntry, stack []*YAMLEntry) {
+	if len(stack) > 0 {
+		entry.Parent = stack[len(stack)-1]
+		entry.Parent.Children = append(entry.Parent.Children, entry)
+		entry.Path = JoinYAMLPath(entry.Parent.Path, entry.Key)
+	} else {
+		entry.Path = entry.Key
+	}
+	d.Entries = append(d.Entries, entry)
+}
+
+// parseYAMLLine extracts the key and inline value of a "key: value" line,
+// returning nil when the line is not a mapping entry
+func parseYAMLLine(line string) *YAMLEntry {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	rest := line[indent:]
+
+	keyEnd := -1
+	var key string
+	switch rest[0] {
+	case '"', '\'':
+		closing := findClosingQuote(rest, rest[0])
+		if closing < 0 || closing+1 >= len(rest) || rest[closing+1] != ':' {
+			return nil
+		}
+		key = UnquoteYAMLScalar(rest[:closing+1])
+		keyEnd = closing + 1
+	default:
+		for i := 0; i < len(rest); i++ {
+			if rest[i] == ':' && (i+1 == len(rest) || rest[i+1] == ' ' || rest[i+1] == '\t') {
+				keyEnd = i
+				break
+			}
+			if rest[i] == '#' && i > 0 && rest[i-1] == ' ' {
+				return nil
+			}
+		}
+		if keyEnd <= 0 {
+			return nil
+		}
+		key = strings.TrimSpace(rest[:keyEnd])
+	}
+
+	entry := &YAMLEntry{
+		Key:      key,
+		Indent:   indent,
+		KeyStart: indent,
+		KeyEnd:   indent + keyEnd,
+	}
+
+	// Value after the colon, up to an unquoted trailing comment
+	valueStart := keyEnd + 1
+	for valueStart < len(rest) && (rest[valueStart] == ' ' || rest[valueStart] == '\t') {
+		valueStart++
+	}
+	rawValue := stripYAMLComment(rest[valueStart:])
+	entry.ValueStart = indent + valueStart
+	entry.RawValue = rawValue
+	if entry.HasValue() {
+		entry.Value = UnquoteYAMLScalar(rawValue)
+	}
+	return entry
+}
+
+// stripYAMLComment removes a trailing " # comment" that is not inside quotes
+func stripYAMLComment(value string) string {
+	if value == "" || value[0] == '#' {
+		return ""
+	}
+	if value[0] == '"' || value[0] == '\'' {
+		if closing := findClosingQuote(value, value[0]); closing >= 0 {
+			return value[:closing+1]
+		}
+	}
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = value[:idx]
+	}
+	if idx := strings.Index(value, "\t#"); idx >= 0 {
+		value = value[:idx]
+	}
+	return strings.TrimRight(value, " \t")
+}
+
+// findClosingQuote returns the index of the quote that closes the scalar starting at s[0]
+func findClosingQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// UnquoteYAMLScalar removes single or double quotes from a scalar, resolving escapes
+func UnquoteYAMLScalar(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	switch {
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	case value[0] == '"' && value[len(value)-1] == '"':
+		inner := value[1 : len(value)-1]
+		var b strings.Builder
+		for i := 0; i < len(inner); i++ {
+			if inner[i] == '\\' && i+1 < len(inner) {
+				i++
+				switch inner[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case 'r':
+					b.WriteByte('\r')
+				case '0':
+					b.WriteByte(0)
+				case 'x', 'u', 'U':
+					width := map[byte]int{'x': 2, 'u': 4, 'U': 8}[inner[i]]
+					if i+width < len(inner) {
+						if code, err := strconv.ParseUint(inner[i+1:i+1+width], 16, 32); err == nil {
+							b.WriteRune(rune(code))
+							i += width
+							continue
+						}
+					}
+					b.WriteByte(inner[i])
+				default:
+					b.WriteByte(inner[i])
+				}
+				continue
+			}
+			b.WriteByte(inner[i])
+		}
+		return b.String()
+	}
+	return value
+}
+
+// FormatYAMLScalar renders a value as a YAML scalar, keeping the quote style of
+// the value it replaces where possible and quoting plain values only when needed
+func FormatYAMLScalar(value, previousRaw string) string {
+	if previousRaw != "" {
+		switch previousRaw[0] {
+		case '"':
+			return QuoteYAMLScalar(value, '"')
+		case '\'':
+			// Single quotes cannot escape line breaks
+			if !strings.ContainsAny(value, "\n\r") {
+				return QuoteYAMLScalar(value, '\'')
+			}
+			return QuoteYAMLScalar(value, '"')
+		}
+	}
+	if NeedsYAMLQuotes(value) {
+		return QuoteYAMLScalar(value, '"')
+	}
+	return value
+}
+
+// QuoteYAMLScalar wraps a value in the given quote character, escaping as required
+func QuoteYAMLScalar(value string, quote byte) string {
+	if quote == '\'' {
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	escaped := strings.ReplaceAll(value, "\\", "\\\\")
+	escaped = strings.ReplaceAll(escaped, "\"", "\\\"")
+	escaped = strings.ReplaceAll(escaped, "\n", "\\n")
+	escaped = strings.ReplaceAll(escaped, "\t", "\\t")
+	escaped = strings.ReplaceAll(escaped, "\r", "\\r")
+	return "\"" + escaped + "\""
+}
+
+// NeedsYAMLQuotes reports whether a plain scalar would be misread by a YAML parser
+func NeedsYAMLQuotes(value string) bool {
+	if value == "" || value != strings.TrimSpace(value) {
+		return true
+	}
+	if strings.ContainsAny(value[:1], "-?:,[]{}#&*!|>'\"%@`") && !isNumeric(value) {
+		return true
+	}
+	return strings.Contains(value, ": ") || strings.Contains(value, " #") ||
+		strings.ContainsAny(value, "\n\t") || strings.HasSuffix(value, ":")
+}
+
+// isNumeric reports whether a value is a plain (possibly negative) decimal number
+func isNumeric(value string) bool {
+	digits := strings.TrimPrefix(value, "-")
+	if digits == "" {
+		return false
+	}
+	for _, c := range digits {
+		if (c < '0' || c > '9') && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// isYAMLComment reports whether a line only holds a comment
+func isYAMLComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+// isBlockScalarIndicator reports whether an inline value starts a "|" or ">" block scalar
+func isBlockScalarIndicator(rawValue string) bool {
+	if rawValue == "" || (rawValue[0] != '|' && rawValue[0] != '>') {
+		return false
+	}
+	return strings.Trim(rawValue[1:], "+-0123456789") == ""
+}
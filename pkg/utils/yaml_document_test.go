@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// yamlEntrySummaries describes the entries of a document as
+// document:path=value@line-end, with the raw value for flow collections
+func yamlEntrySummaries(doc *YAMLDocument) []string {
+	var summaries []string
+	for _, entry := range doc.Entries {
+		summaries = append(summaries, fmt.Sprintf("%d:%s=%s@%d-%d", entry.Document, entry.Path, entry.Value, entry.Line, entry.End))
+	}
+	return summaries
+}
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		expected  []string
+		documents []int
+	}{
+		{
+			name:    "nested mappings and comments",
+			content: "# header\nserver:\n  # the port\n  port: 8080 # trailing\n  address: \"a # b\"\nspring:\n  application:\n    name: app\n",
+			expected: []string{
+				"0:server=@1-5", "0:server.port=8080@3-4", "0:server.address=a # b@4-5",
+				"0:spring=@5-8", "0:spring.application=@6-8", "0:spring.application.name=app@7-8",
+			},
+			documents: []int{0},
+		},
+		{
+			name:    "block sequences",
+			content: "list:\n  - a\n  - name: b\n    port: 1\n  -\n    name: c\nnested:\n- - x\n  - y\n",
+			expected: []string{
+				"0:list=@0-6", "0:list[0]=a@1-2", "0:list[1]=@2-4", "0:list[1].name=b@2-3", "0:list[1].port=1@3-4",
+				"0:list[2]=@4-6", "0:list[2].name=c@5-6",
+				"0:nested=@6-9", "0:nested[0]=@7-9", "0:nested[0][0]=x@7-8", "0:nested[0][1]=y@8-9",
+			},
+			documents: []int{0},
+		},
+		{
+			name:    "block scalars",
+			content: "script: |\n  key: not a key\n  - not an item\n\n  # not a comment\nfolded: >-\n  text\nnext: 1\n",
+			expected: []string{
+				"0:script=@0-5", "0:folded=@5-7", "0:next=1@7-8",
+			},
+			documents: []int{0},
+		},
+		{
+			name:    "flow collections",
+			content: "include: [health, info]\nmap: {a: 1, b: [2, 3]}\nempty: []\n",
+			expected: []string{
+				"0:include=[health, info]@0-1", "0:map={a: 1, b: [2, 3]}@1-2", "0:empty=[]@2-3",
+			},
+			documents: []int{0},
+		},
+		{
+			name:    "quoted keys and escapes",
+			content: "\"a.b\": 1\n'c:d': 'it''s'\ne: \"tab\\there\\u00e9\"\n",
+			expected: []string{
+				"0:a.b=1@0-1", "0:c:d=it's@1-2", "0:e=tab\there\u00e9@2-3",
+			},
+			documents: []int{0},
+		},
+		{
+			name:    "multi-document",
+			content: "---\na: 1\n---\nb: 2\n--- # third\nc: 3\n...\n",
+			expected: []string{
+				"0:a=1@1-2", "1:b=2@3-4", "2:c=3@5-6",
+			},
+			documents: []int{1, 3, 5},
+		},
+		{
+			name:      "no trailing newline",
+			content:   "a:\n  b: 1",
+			expected:  []string{"0:a=@0-2", "0:a.b=1@1-2"},
+			documents: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := ParseYAML(tt.content)
+			if actual := doc.String(); actual != tt.content {
+				t.Errorf("String() = %q, want %q", actual, tt.content)
+			}
+			if actual := yamlEntrySummaries(doc); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("entries = %q, want %q", actual, tt.expected)
+			}
+			if !reflect.DeepEqual(doc.DocumentStarts, tt.documents) {
+				t.Errorf("DocumentStarts = %v, want %v", doc.DocumentStarts, tt.documents)
+			}
+		})
+	}
+}
+
+func TestYAMLDocumentEdits(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		edit     func(doc *YAMLDocument)
+		expected string
+	}{
+		{
+			name:    "set value keeps the trailing comment",
+			content: "server:\n  port: 8080 # http",
+			edit: func(doc *YAMLDocument) {
+				doc.SetValue(doc.Find("server.port"), "9090")
+			},
+			expected: "server:\n  port: 9090 # http",
+		},
+		{
+			name:    "set value on a key without value",
+			content: "a:\n",
+			edit: func(doc *YAMLDocument) {
+				doc.SetValue(doc.Find("a"), "1")
+			},
+			expected: "a: 1\n",
+		},
+		{
+			name:    "set key quotes it when needed",
+			content: "a:\n  old: 1\n",
+			edit: func(doc *YAMLDocument) {
+				doc.SetKey(doc.Find("a.old"), "new:key")
+			},
+			expected: "a:\n  \"new:key\": 1\n",
+		},
+		{
+			name:    "set value of a sequence item",
+			content: "list:\n  - a\n  - 'b'\n",
+			edit: func(doc *YAMLDocument) {
+				entry := doc.Find("list[1]")
+				doc.SetValue(entry, FormatYAMLScalar("it's", entry.RawValue))
+			},
+			expected: "list:\n  - a\n  - 'it''s'\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := ParseYAML(tt.content)
+			tt.edit(doc)
+			if actual := doc.String(); actual != tt.expected {
+				t.Errorf("String() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestYAMLScalarsRoundTrip(t *testing.T) {
+	values := []string{
+		"plain",
+		"",
+		" padded ",
+		"a: b",
+		"a #b",
+		"#comment",
+		"- item",
+		"it's \"quoted\"",
+		"tab\tnewline\n",
+		"back\\slash",
+		"{braces}",
+		"*alias",
+		"true",
+		"8080",
+	}
+	for _, value := range values {
+		for _, previous := range []string{"", "'x'", "\"x\""} {
+			t.Run(value+" after "+previous, func(t *testing.T) {
+				formatted := FormatYAMLScalar(value, previous)
+				doc := ParseYAML("key: " + formatted)
+				if len(doc.Entries) != 1 {
+					t.Fatalf("%q parsed into %d entries", formatted, len(doc.Entries))
+				}
+				if actual := doc.Entries[0].Value; actual != value {
+					t.Errorf("%q reads back as %q, want %q", formatted, actual, value)
+				}
+			})
+		}
+	}
+}
+
+func TestFlowSequenceItems(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected []string
+		ok       bool
+	}{
+		{"[health, info]", []string{"health", "info"}, true},
+		{"[ 'a, b' , \"c\" ]", []string{"a, b", "c"}, true},
+		{"[a, b,]", []string{"a", "b"}, true},
+		{"[]", nil, true},
+		{"[[a], b]", nil, false},
+		{"[{a: 1}]", nil, false},
+		{"[a: 1]", nil, false},
+		{"[a, b", nil, false},
+		{"{a: 1}", nil, false},
+		{"plain", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			entry := ParseYAML("key: " + tt.raw).Entries[0]
+			items, ok := entry.FlowSequenceItems()
+			var values []string
+			for _, item := range items {
+				values = append(values, item.Value)
+			}
+			if ok != tt.ok || !reflect.DeepEqual(values, tt.expected) {
+				t.Errorf("FlowSequenceItems() = %q, %v, want %q, %v", values, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
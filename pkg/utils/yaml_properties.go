@@ -0,0 +1,561 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// YAML 1.1 implicit types resolved by SnakeYAML, which Spring Boot uses to load
+// YAML; timestamps are left out because Spring Boot keeps them as strings
+var (
+	yamlNullPattern  = regexp.MustCompile(`^(?:~|null|Null|NULL)$`)
+	yamlBoolPattern  = regexp.MustCompile(`^(?:yes|Yes|YES|no|No|NO|true|True|TRUE|false|False|FALSE|on|On|ON|off|Off|OFF)$`)
+	yamlIntPattern   = regexp.MustCompile(`^(?:[-+]?0b_*[0-1]+[0-1_]*|[-+]?0_*[0-7]+[0-7_]*|[-+]?(?:0|[1-9][0-9_]*)|[-+]?0x_*[0-9a-fA-F]+[0-9a-fA-F_]*|[-+]?[1-9][0-9_]*(?::[0-5]?[0-9])+)$`)
+	yamlFloatPattern = regexp.MustCompile(`^(?:[-+]?(?:\.[0-9]+|[0-9_]+(?:\.[0-9_]*)?)(?:[eE][-+]?[0-9]+)?|[-+]?[0-9][0-9_]*(?::[0-5]?[0-9])+\.[0-9_]*|[-+]?\.(?:inf|Inf|INF)|\.(?:nan|NaN|NAN))$`)
+)
+
+// YAMLProperty is a property defined by a YAML document, flattened to its
+// properties-file form
+type YAMLProperty struct {
+	Key     string // flattened key, e.g. "server.port" or "my.list[0].name"
+	Value   string // the string Spring binds, with implicit types resolved
+	Line    int    // first line of the value
+	End     int    // one past the last line of the value
+	Comment string // trailing comment of the value line, including "#"
+}
+
+// FlattenDocument returns the properties defined by one document, in file order
+func (d *YAMLDocument) FlattenDocument(document int) ([]YAMLProperty, error) {
+	var properties []YAMLProperty
+	skipUntil := -1
+	for _, entry := range d.Entries {
+		if entry.Document != document || entry.Line < skipUntil {
+			continue
+		}
+		if entry.Item {
+			// A document that is a sequence defines no properties
+			skipUntil = entry.End
+			continue
+		}
+		comment := trailingYAMLComment(d.Lines[entry.Line])
+
+		switch {
+		case entry.RawValue != "":
+			end := entry.End
+			if isBlockScalarIndicator(entry.RawValue) {
+				// The blank lines after a block scalar belong to it, which "|+" keeps
+				for end < d.DocumentEnd(document) && strings.TrimSpace(d.Lines[end]) == "" {
+					end++
+				}
+			}
+			flattened, err := flattenYAMLValue(entry.Path, entry.RawValue, d.Lines[entry.Line:end], entry.Line, end < len(d.Lines))
+			if err != nil {
+				return nil, err
+			}
+			if len(flattened) > 0 {
+				flattened[0].Comment = comment
+				if isBlockScalarIndicator(entry.RawValue) {
+					flattened[0].End = entry.End
+				}
+			}
+			properties = append(properties, flattened...)
+			skipUntil = entry.End
+		case isYAMLSequenceBody(d.Lines[entry.Line+1 : entry.End]):
+			flattened, err := flattenYAMLBlock(entry.Path, d.Lines[entry.Line+1:entry.End], entry.Line+1, entry.End < len(d.Lines))
+			if err != nil {
+				return nil, err
+			}
+			properties = append(properties, flattened...)
+			skipUntil = entry.End
+		case len(entry.Children) == 0:
+			// A key without a value is null, which Spring binds as an empty string
+			properties = append(properties, YAMLProperty{Key: entry.Path, Line: entry.Line, End: entry.Line + 1, Comment: comment})
+		}
+	}
+	return properties, nil
+}
+
+// ResolveYAMLScalar returns the string Spring binds for a scalar as written in
+// YAML: quotes are removed and implicitly typed values such as "on", "0x1F" or
+// "1.50" take the form of the Java value they are read as
+func ResolveYAMLScalar(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	if raw[0] == '"' || raw[0] == '\'' {
+		return UnquoteYAMLScalar(raw)
+	}
+	if strings.HasPrefix(raw, "!!str ") {
+		return UnquoteYAMLScalar(strings.TrimSpace(raw[len("!!str "):]))
+	}
+
+	switch {
+	case yamlNullPattern.MatchString(raw):
+		return ""
+	case yamlBoolPattern.MatchString(raw):
+		switch strings.ToLower(raw) {
+		case "yes", "true", "on":
+			return "true"
+		}
+		return "false"
+	case yamlIntPattern.MatchString(raw):
+		if value, ok := resolveYAMLInt(raw); ok {
+			return value
+		}
+	case yamlFloatPattern.MatchString(raw):
+		if value, ok := resolveYAMLFloat(raw); ok {
+			return value
+		}
+	}
+	return raw
+}
+
+// FormatYAMLValue renders a string as a YAML scalar that ResolveYAMLScalar reads
+// back unchanged, using a plain scalar whenever that is safe
+func FormatYAMLValue(value string) string {
+	if !NeedsYAMLQuotes(value) && ResolveYAMLScalar(value) == value && !strings.ContainsAny(value, "\r\f\x00") {
+		return value
+	}
+	for _, r := range value {
+		if r < ' ' {
+			// Control characters can only be escaped in double quotes
+			return QuoteYAMLScalar(value, '"')
+		}
+	}
+	return QuoteYAMLScalar(value, '\'')
+}
+
+// resolveYAMLInt converts a YAML 1.1 integer to its decimal form
+func resolveYAMLInt(raw string) (string, bool) {
+	digits := strings.ReplaceAll(raw, "_", "")
+	sign := ""
+	if digits[0] == '-' || digits[0] == '+' {
+		if digits[0] == '-' {
+			sign = "-"
+		}
+		digits = digits[1:]
+	}
+
+	value := new(big.Int)
+	ok := true
+	switch {
+	case strings.Contains(digits, ":"):
+		// Sexagesimal, e.g. "1:30" is 90
+		for _, part := range strings.Split(digits, ":") {
+			n, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return "", false
+			}
+			value.Mul(value, big.NewInt(60))
+			value.Add(value, big.NewInt(n))
+		}
+	case strings.HasPrefix(digits, "0b"):
+		_, ok = value.SetString(digits[2:], 2)
+	case strings.HasPrefix(digits, "0x"):
+		_, ok = value.SetString(digits[2:], 16)
+	case len(digits) > 1 && digits[0] == '0':
+		_, ok = value.SetString(digits[1:], 8)
+	default:
+		_, ok = value.SetString(digits, 10)
+	}
+	if !ok {
+		return "", false
+	}
+	if sign == "-" {
+		value.Neg(value)
+	}
+	return value.String(), true
+}
+
+// resolveYAMLFloat converts a YAML 1.1 float to the form of Java's Double.toString
+func resolveYAMLFloat(raw string) (string, bool) {
+	digits := strings.ReplaceAll(raw, "_", "")
+	switch strings.ToLower(strings.TrimPrefix(digits, "+")) {
+	case ".inf":
+		return "Infinity", true
+	case "-.inf":
+		return "-Infinity", true
+	case ".nan":
+		return "NaN", true
+	}
+
+	var value float64
+	if strings.Contains(digits, ":") {
+		// Sexagesimal, e.g. "1:30.5" is 90.5
+		negative := strings.HasPrefix(digits, "-")
+		parts := strings.Split(strings.TrimLeft(digits, "+-"), ":")
+		for i, part := range parts {
+			n, err := strconv.ParseFloat(part, 64)
+			if err != nil || (i < len(parts)-1 && strings.Contains(part, ".")) {
+				return "", false
+			}
+			value = value*60 + n
+		}
+		if negative {
+			value = -value
+		}
+	} else {
+		parsed, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return "", false
+		}
+		value = parsed
+	}
+	return javaDoubleString(value), true
+}
+
+// javaDoubleString formats a float the way Java's Double.toString does
+func javaDoubleString(value float64) string {
+	abs := math.Abs(value)
+	switch {
+	case value == 0:
+		if math.Signbit(value) {
+			return "-0.0"
+		}
+		return "0.0"
+	case abs >= 1e-3 && abs < 1e7:
+		s := strconv.FormatFloat(value, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		return s
+	}
+
+	s := strconv.FormatFloat(value, 'E', -1, 64)
+	mantissa, exponent, _ := strings.Cut(s, "E")
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	n, _ := strconv.Atoi(exponent)
+	return mantissa + "E" + strconv.Itoa(n)
+}
+
+// flattenYAMLValue flattens the inline value of an entry; lines start at the
+// line holding the value, which is line firstLine of the document, and
+// finalBreak tells whether the last of them ends with a line break
+func flattenYAMLValue(key, raw string, lines []string, firstLine int, finalBreak bool) ([]YAMLProperty, error) {
+	end := firstLine + len(lines)
+	switch {
+	case isBlockScalarIndicator(raw):
+		return []YAMLProperty{{Key: key, Value: blockScalarValue(raw, lines[1:], finalBreak), Line: firstLine, End: end}}, nil
+	case raw[0] == '[' || raw[0] == '{':
+		parser := &flowParser{text: raw, line: firstLine}
+		properties, err := parser.parse(key)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", firstLine+1, err)
+		}
+		return properties, nil
+	case raw[0] == '&' || raw[0] == '*':
+		return nil, fmt.Errorf("line %d: anchors and aliases are not supported", firstLine+1)
+	}
+	return []YAMLProperty{{Key: key, Value: ResolveYAMLScalar(raw), Line: firstLine, End: firstLine + 1}}, nil
+}
+
+// flattenYAMLBlock flattens nested block content, a mapping or a sequence, whose
+// first line is line firstLine of the document
+func flattenYAMLBlock(key string, lines []string, firstLine int, finalBreak bool) ([]YAMLProperty, error) {
+	if isYAMLSequenceBody(lines) {
+		return flattenYAMLSequence(key, lines, firstLine, finalBreak)
+	}
+
+	content := strings.Join(lines, "\n")
+	if finalBreak {
+		content += "\n"
+	}
+	block := ParseYAML(content)
+	if len(block.Entries) == 0 {
+		// Nothing but comments: a null value
+		return []YAMLProperty{{Key: key, Line: firstLine, End: firstLine + 1}}, nil
+	}
+	properties, err := block.FlattenDocument(0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range properties {
+		properties[i].Key = JoinYAMLPath(key, properties[i].Key)
+		properties[i].Line += firstLine
+		properties[i].End += firstLine
+	}
+	return properties, nil
+}
+
+// flattenYAMLSequence flattens the items of a block sequence into indexed keys
+func flattenYAMLSequence(key string, lines []string, firstLine int, finalBreak bool) ([]YAMLProperty, error) {
+	var starts []int
+	itemIndent := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || isYAMLComment(line) {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if itemIndent < 0 {
+			itemIndent = indent
+		}
+		if indent < itemIndent || (indent == itemIndent && !isYAMLSequenceItem(trimmed)) {
+			return nil, fmt.Errorf("line %d: unexpected content in sequence", firstLine+i+1)
+		}
+		if indent == itemIndent {
+			starts = append(starts, i)
+		}
+	}
+
+	var properties []YAMLProperty
+	for index, start := range starts {
+		end := len(lines)
+		if index+1 < len(starts) {
+			end = starts[index+1]
+		}
+		itemKey := fmt.Sprintf("%s[%d]", key, index)
+		itemBreak := end < len(lines) || finalBreak
+
+		// Replace the dash with a space so the item content keeps its columns
+		itemLines := append([]string{}, lines[start:end]...)
+		first := itemLines[0]
+		itemLines[0] = first[:itemIndent] + " " + first[itemIndent+1:]
+		rest := strings.TrimSpace(itemLines[0])
+
+		var flattened []YAMLProperty
+		var err error
+		isFlow := rest != "" && (rest[0] == '[' || rest[0] == '{')
+		if rest == "" || isYAMLComment(rest) || isYAMLSequenceItem(rest) || (!isFlow && parseYAMLLine(itemLines[0]) != nil) {
+			flattened, err = flattenYAMLBlock(itemKey, itemLines, firstLine+start, itemBreak)
+		} else {
+			flattened, err = flattenYAMLValue(itemKey, stripYAMLComment(rest), itemLines, firstLine+start, itemBreak)
+			if err == nil && len(flattened) > 0 {
+				flattened[0].Comment = trailingYAMLComment(first)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, flattened...)
+	}
+	return properties, nil
+}
+
+// blockScalarValue returns the content of a literal ("|") or folded (">") block
+// scalar; finalBreak tells whether its last line ends with a line break
+func blockScalarValue(indicator string, lines []string, finalBreak bool) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			indent = len(line) - len(strings.TrimLeft(line, " "))
+			break
+		}
+	}
+	if indent < 0 {
+		return ""
+	}
+
+	var content []string
+	for _, line := range lines {
+		if len(line) >= indent {
+			content = append(content, line[indent:])
+		} else {
+			content = append(content, "")
+		}
+	}
+	trailing := 0
+	for len(content) > 0 && strings.TrimSpace(content[len(content)-1]) == "" {
+		content = content[:len(content)-1]
+		trailing++
+	}
+
+	var value string
+	if indicator[0] == '|' {
+		value = strings.Join(content, "\n")
+	} else {
+		value = foldBlockScalar(content)
+	}
+
+	// Line breaks after the last content line, one per trailing blank line
+	breaks := trailing
+	if finalBreak {
+		breaks++
+	}
+	switch {
+	case strings.Contains(indicator, "-") || breaks == 0:
+		return value
+	case strings.Contains(indicator, "+"):
+		return value + strings.Repeat("\n", breaks)
+	default:
+		return value + "\n"
+	}
+}
+
+// foldBlockScalar joins the lines of a folded block scalar: single line breaks
+// become spaces, blank lines become line breaks and more-indented lines are kept
+func foldBlockScalar(lines []string) string {
+	var b strings.Builder
+	blank := 0
+	first := true
+	previousMoreIndented := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			blank++
+			continue
+		}
+		moreIndented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		if !first {
+			switch {
+			case moreIndented || previousMoreIndented:
+				b.WriteString(strings.Repeat("\n", blank+1))
+			case blank == 0:
+				b.WriteByte(' ')
+			default:
+				b.WriteString(strings.Repeat("\n", blank))
+			}
+		}
+		b.WriteString(line)
+		blank = 0
+		first = false
+		previousMoreIndented = moreIndented
+	}
+	return b.String()
+}
+
+// isYAMLSequenceBody reports whether the first content line of a block is a sequence item
+func isYAMLSequenceBody(lines []string) bool {
+	for _, line := range lines {
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !isYAMLComment(line) {
+			return isYAMLSequenceItem(trimmed)
+		}
+	}
+	return false
+}
+
+// isYAMLSequenceItem reports whether a trimmed line starts a block sequence item
+func isYAMLSequenceItem(trimmed string) bool {
+	return trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+}
+
+// trailingYAMLComment returns the "# comment" ending a line, or ""
+func trailingYAMLComment(line string) string {
+	rest := strings.TrimLeft(line, " ")
+	for isYAMLSequenceItem(rest) {
+		rest = strings.TrimLeft(strings.TrimPrefix(rest, "-"), " ")
+	}
+	if entry := parseYAMLLine(rest); entry != nil {
+		rest = strings.TrimLeft(rest[entry.KeyEnd+1:], " \t")
+	}
+	comment := strings.TrimSpace(rest[len(stripYAMLComment(rest)):])
+	if strings.HasPrefix(comment, "#") {
+		return comment
+	}
+	return ""
+}
+
+// flowParser flattens a flow collection such as "[a, b]" or "{x: 1}"
+type flowParser struct {
+	text string
+	pos  int
+	line int
+}
+
+// parse flattens the flow value at the current position under key
+func (p *flowParser) parse(key string) ([]YAMLProperty, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.text) {
+		return []YAMLProperty{p.property(key, "")}, nil
+	}
+
+	switch p.text[p.pos] {
+	case '[':
+		p.pos++
+		var properties []YAMLProperty
+		for index := 0; ; index++ {
+			p.skipSpaces()
+			if p.consume(']') {
+				if index == 0 {
+					properties = append(properties, p.property(key, ""))
+				}
+				return properties, nil
+			}
+			item, err := p.parse(fmt.Sprintf("%s[%d]", key, index))
+			if err != nil {
+				return nil, err
+			}
+			properties = append(properties, item...)
+			if err := p.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		p.pos++
+		var properties []YAMLProperty
+		for count := 0; ; count++ {
+			p.skipSpaces()
+			if p.consume('}') {
+				if count == 0 {
+					properties = append(properties, p.property(key, ""))
+				}
+				return properties, nil
+			}
+			name := ResolveYAMLScalar(p.scalar(true))
+			p.skipSpaces()
+			if !p.consume(':') {
+				return nil, fmt.Errorf("expected ':' in flow mapping")
+			}
+			value, err := p.parse(JoinYAMLPath(key, name))
+			if err != nil {
+				return nil, err
+			}
+			properties = append(properties, value...)
+			if err := p.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return []YAMLProperty{p.property(key, ResolveYAMLScalar(p.scalar(false)))}, nil
+}
+
+// scalar reads a quoted or plain scalar inside a flow collection
+func (p *flowParser) scalar(isKey bool) string {
+	start := p.pos
+	if p.pos < len(p.text) && (p.text[p.pos] == '"' || p.text[p.pos] == '\'') {
+		if closing := findClosingQuote(p.text[p.pos:], p.text[p.pos]); closing >= 0 {
+			p.pos += closing + 1
+			return p.text[start:p.pos]
+		}
+	}
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if c == ',' || c == ']' || c == '}' || (isKey && c == ':') {
+			break
+		}
+		p.pos++
+	}
+	return strings.TrimSpace(p.text[start:p.pos])
+}
+
+// separator consumes the "," between items, or stops before the closing bracket
+func (p *flowParser) separator(closing byte) error {
+	p.skipSpaces()
+	if p.consume(',') || (p.pos < len(p.text) && p.text[p.pos] == closing) {
+		return nil
+	}
+	return fmt.Errorf("expected ',' or '%c' in flow collection", closing)
+}
+
+func (p *flowParser) consume(c byte) bool {
+	if p.pos < len(p.text) && p.text[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *flowParser) skipSpaces() {
+	for p.pos < len(p.text) && (p.text[p.pos] == ' ' || p.text[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *flowParser) property(key, value string) YAMLProperty {
+	return YAMLProperty{Key: key, Value: value, Line: p.line, End: p.line + 1}
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// yamlPropertySummaries describes flattened properties as key=value@line-end
+// followed by the trailing comment, if any
+func yamlPropertySummaries(properties []YAMLProperty) []string {
+	var summaries []string
+	for _, property := range properties {
+		summary := fmt.Sprintf("%s=%s@%d-%d", property.Key, property.Value, property.Line, property.End)
+		if property.Comment != "" {
+			summary += " " + property.Comment
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func TestFlattenDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected [][]string
+	}{
+		{
+			name:    "mappings and comments",
+			content: "# header\nserver:\n  port: 8080 # http\n  ssl:\n    enabled: on\nempty:\n",
+			expected: [][]string{{
+				"server.port=8080@2-3 # http", "server.ssl.enabled=true@4-5", "empty=@5-6",
+			}},
+		},
+		{
+			name:    "block sequences",
+			content: "list:\n  - a\n  - name: b\n    port: 0x10\nnested:\n  - - x\n    - y\n",
+			expected: [][]string{{
+				"list[0]=a@1-2", "list[1].name=b@2-3", "list[1].port=16@3-4",
+				"nested[0][0]=x@5-6", "nested[0][1]=y@6-7",
+			}},
+		},
+		{
+			name:    "flow collections",
+			content: "include: [health, 'a, b']\nmap: {a: 1, b: [2, {c: 3}]}\nempty: []\n",
+			expected: [][]string{{
+				"include[0]=health@0-1", "include[1]=a, b@0-1",
+				"map.a=1@1-2", "map.b[0]=2@1-2", "map.b[1].c=3@1-2", "empty=@2-3",
+			}},
+		},
+		{
+			name:    "block scalars",
+			content: "literal: |\n  one\n    two\n\nfolded: >\n  one\n  two\n\n  three\nstrip: |-\n  text\n\nkeep: |+\n  text\n\nlast: 1\n",
+			expected: [][]string{{
+				"literal=one\n  two\n@0-3", "folded=one two\nthree\n@4-9", "strip=text@9-11", "keep=text\n\n@12-14", "last=1@15-16",
+			}},
+		},
+		{
+			name:     "kept block scalar at the end of the file",
+			content:  "keep: |+\n  text\n\n",
+			expected: [][]string{{"keep=text\n\n@0-2"}},
+		},
+		{
+			name:    "block scalars in sequence items",
+			content: "list:\n  - |\n    one\n  - key: >-\n      two\n  - |\n    three",
+			expected: [][]string{{
+				"list[0]=one\n@1-3", "list[1].key=two@3-5", "list[2]=three@5-7",
+			}},
+		},
+		{
+			name:    "escapes",
+			content: "double: \"tab\\t\\\"q\\\" \\u00e9\\\\\"\nsingle: 'it''s # not a comment'\ntagged: !!str 0x10\n",
+			expected: [][]string{{
+				"double=tab\t\"q\" é\\@0-1", "single=it's # not a comment@1-2", "tagged=0x10@2-3",
+			}},
+		},
+		{
+			name:    "multi-document",
+			content: "a: 1\n---\nspring:\n  config:\n    activate:\n      on-profile: dev\na: 2\n",
+			expected: [][]string{
+				{"a=1@0-1"},
+				{"spring.config.activate.on-profile=dev@5-6", "a=2@6-7"},
+			},
+		},
+		{
+			name:     "no trailing newline",
+			content:  "a:\n  b: |\n    text",
+			expected: [][]string{{"a.b=text@1-3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := ParseYAML(tt.content)
+			if doc.DocumentCount() != len(tt.expected) {
+				t.Fatalf("DocumentCount() = %d, want %d", doc.DocumentCount(), len(tt.expected))
+			}
+			for document, expected := range tt.expected {
+				properties, err := doc.FlattenDocument(document)
+				if err != nil {
+					t.Fatalf("FlattenDocument(%d) failed: %v", document, err)
+				}
+				if actual := yamlPropertySummaries(properties); !reflect.DeepEqual(actual, expected) {
+					t.Errorf("FlattenDocument(%d) = %q, want %q", document, actual, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveYAMLScalar(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"text", "text"},
+		{"~", ""},
+		{"null", ""},
+		{"yes", "true"},
+		{"Off", "false"},
+		{"0x1F", "31"},
+		{"0b101", "5"},
+		{"010", "8"},
+		{"1_000", "1000"},
+		{"1:30", "90"},
+		{"1.50", "1.5"},
+		{"1e3", "1000.0"},
+		{".inf", "Infinity"},
+		{"'yes'", "yes"},
+		{"\"0x1F\"", "0x1F"},
+		{"1.2.3", "1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if actual := ResolveYAMLScalar(tt.raw); actual != tt.expected {
+				t.Errorf("ResolveYAMLScalar(%q) = %q, want %q", tt.raw, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatYAMLValueRoundTrip(t *testing.T) {
+	values := []string{"text", "", "yes", "0x1F", "1.50", "~", "a: b", "# c", " padded", "it's", "line\nbreak", "cr\r"}
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			formatted := FormatYAMLValue(value)
+			if actual := ResolveYAMLScalar(formatted); actual != value {
+				t.Errorf("%q resolves to %q, want %q", formatted, actual, value)
+			}
+		})
+	}
+}